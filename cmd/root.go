@@ -5,10 +5,13 @@ package cmd
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"io"
+	"iter"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/HrithikSawant/go-json-parser/internal/utils"
@@ -17,7 +20,48 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var filePath string
+var (
+	filePath       string
+	trace          bool
+	maxDepth       int
+	duplicateKeys  string
+	maxErrors      int
+	dialect        string
+	trailingCommas string
+	ndjson         bool
+	seq            bool
+	skipBlank      bool
+	strict         bool
+	bom            string
+)
+
+// bomPolicies maps the values of --bom to lexer policies.
+var bomPolicies = map[string]lexer.BOMPolicy{
+	"reject": lexer.BOMReject,
+	"strip":  lexer.BOMStrip,
+}
+
+// trailingCommaPolicies maps the values of --trailing-commas to parser
+// policies.
+var trailingCommaPolicies = map[string]parser.TrailingCommaPolicy{
+	"reject": parser.TrailingCommaReject,
+	"allow":  parser.TrailingCommaAllow,
+	"warn":   parser.TrailingCommaWarn,
+}
+
+// dialects maps the values of --dialect to lexer dialects.
+var dialects = map[string]lexer.Dialect{
+	"json":  lexer.DialectJSON,
+	"json5": lexer.DialectJSON5,
+	"jsonc": lexer.DialectJSONC,
+}
+
+// duplicateKeyPolicies maps the values of --duplicate-keys to parser policies.
+var duplicateKeyPolicies = map[string]parser.DuplicateKeyPolicy{
+	"allow":  parser.DuplicateKeepAll,
+	"reject": parser.DuplicateReject,
+	"warn":   parser.DuplicateWarn,
+}
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
@@ -28,8 +72,13 @@ var rootCmd = &cobra.Command{
 Examples:
   go-json-parser myfile.json
   echo "{}" | go-json-parser
+  go-json-parser --trace myfile.json
+  go-json-parser --dialect json5 config.json5
+  go-json-parser --dialect jsonc tsconfig.json
+  go-json-parser --ndjson --skip-blank events.ndjson
+  go-json-parser --seq events.json-seq
 
-Output:
+Output with --trace:
   DEBUG: State = Start                | Token = {          | Literal = {
   DEBUG: State = ExpectKeyOrEnd       | Token = }          | Literal = }
   DEBUG: State = Done                 | Token = EOF        | Literal = 
@@ -39,6 +88,11 @@ Output:
 	// Run: func(cmd *cobra.Command, args []string) { },
 
 	Run: func(cmd *cobra.Command, args []string) {
+		if ndjson && seq {
+			fmt.Fprintln(os.Stderr, "Error: --ndjson and --seq cannot be used together")
+			os.Exit(1)
+		}
+
 		var reader io.Reader
 		var err error
 
@@ -52,8 +106,11 @@ Output:
 				os.Exit(1)
 			}
 
-			if filepath.Ext(filePath) != ".json" {
-				fmt.Fprintln(os.Stderr, "Error: File must have a .json extension")
+			if exts := extensions(); len(exts) == 1 && filepath.Ext(filePath) != exts[0] {
+				fmt.Fprintf(os.Stderr, "Error: File must have a %s extension\n", exts[0])
+				os.Exit(1)
+			} else if !slices.Contains(exts, filepath.Ext(filePath)) {
+				fmt.Fprintf(os.Stderr, "Error: File must have one of the extensions %s\n", strings.Join(exts, ", "))
 				os.Exit(1)
 			}
 		} else if utils.IsInputFromPipe() {
@@ -63,31 +120,153 @@ Output:
 			return
 		}
 
-		// Read all input
-		var builder strings.Builder
-		scanner := bufio.NewScanner(reader)
-		for scanner.Scan() {
-			builder.WriteString(scanner.Text())
+		lexOpts, err := lexerOptions()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
 		}
-		if err := scanner.Err(); err != nil {
-			fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		opts, err := parserOptions()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
 
-		// Run lexer and parser
-		input := builder.String()
-		lex := lexer.NewLexer(input)
-		parser := parser.NewParser(lex)
+		if ndjson {
+			n := parser.NewNDJSONReader(reader, lexer.NewBytesLexer(nil, lexOpts...), opts...)
+			if skipBlank {
+				n.SkipBlankLines()
+			}
+			os.Exit(checkRecords(n))
+		}
+		if seq {
+			os.Exit(checkRecords(parser.NewSeqReader(reader, lexer.NewBytesLexer(nil, lexOpts...), opts...)))
+		}
 
-		if parser.Parse() {
-			fmt.Println("Valid JSON structure")
-		} else {
-			fmt.Fprintln(os.Stderr, "Invalid JSON structure")
+		// Run lexer and parser, streaming the input
+		lex := lexer.NewReaderLexer(reader, lexOpts...)
+		p := parser.NewParser(lex, opts...)
+
+		err = p.Parse()
+		for _, d := range p.Diagnostics() {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", d)
+		}
+		var lexErr *lexer.Error
+		if errors.As(err, &lexErr) && lexErr.Reason == lexer.ReasonIO {
+			fmt.Fprintf(os.Stderr, "Error reading input: %v\n", lexErr.Err)
+			os.Exit(1)
+		}
+		var errs parser.ErrorList
+		if errors.As(err, &errs) && len(errs) > 1 {
+			fmt.Fprintf(os.Stderr, "Invalid JSON structure: %d errors\n", len(errs))
+			for _, e := range errs {
+				fmt.Fprintf(os.Stderr, "  %v\n", e)
+			}
+			os.Exit(1)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid JSON structure: %v\n", err)
 			os.Exit(1)
 		}
+		fmt.Println("Valid JSON structure")
 	},
 }
 
+// recordReader is a reader of a stream of documents, such as
+// parser.NDJSONReader and parser.SeqReader.
+type recordReader interface {
+	Records() iter.Seq[parser.Record]
+	Err() error
+	Summary() parser.RecordSummary
+}
+
+// checkRecords reports the invalid documents n reads and a summary, and
+// returns the exit code.
+func checkRecords(n recordReader) int {
+	for rec := range n.Records() {
+		for _, d := range rec.Diagnostics {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", d)
+		}
+		if rec.Err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid record: %v\n", rec.Err)
+		}
+	}
+	if err := n.Err(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		return 1
+	}
+
+	s := n.Summary()
+	fmt.Printf("%d valid, %d invalid records\n", s.Valid, s.Invalid)
+	if s.Invalid > 0 {
+		return 1
+	}
+	return 0
+}
+
+// extensions returns the file extensions that may be read with the
+// selected flags.
+func extensions() []string {
+	exts := []string{".json"}
+	if _, ok := dialects[dialect]; ok && dialect != "json" {
+		exts = append(exts, "."+dialect)
+	}
+	if ndjson {
+		exts = append(exts, ".ndjson", ".jsonl")
+	}
+	if seq {
+		exts = append(exts, ".json-seq")
+	}
+	return exts
+}
+
+// lexerOptions builds the lexer options selected by the command-line flags.
+func lexerOptions() ([]lexer.Option, error) {
+	d, ok := dialects[dialect]
+	if !ok {
+		return nil, fmt.Errorf("invalid --dialect value %q: must be json, json5 or jsonc", dialect)
+	}
+	opts := []lexer.Option{lexer.WithDialect(d)}
+
+	policy, ok := bomPolicies[bom]
+	if !ok {
+		return nil, fmt.Errorf("invalid --bom value %q: must be reject or strip", bom)
+	}
+	opts = append(opts, lexer.WithBOM(policy))
+
+	if strict {
+		opts = append(opts, lexer.Strict())
+	}
+	return opts, nil
+}
+
+// parserOptions builds the parser options selected by the command-line flags.
+func parserOptions() ([]parser.Option, error) {
+	opts := []parser.Option{parser.MaxDepth(maxDepth)}
+	if trace {
+		opts = append(opts, parser.WithTracer(parser.NewTableTracer(os.Stdout)))
+	}
+
+	policy, ok := duplicateKeyPolicies[duplicateKeys]
+	if !ok {
+		return nil, fmt.Errorf("invalid --duplicate-keys value %q: must be allow, reject or warn", duplicateKeys)
+	}
+	opts = append(opts, parser.WithDuplicateKeys(policy))
+
+	if trailingCommas != "" {
+		policy, ok := trailingCommaPolicies[trailingCommas]
+		if !ok {
+			return nil, fmt.Errorf("invalid --trailing-commas value %q: must be reject, allow or warn", trailingCommas)
+		}
+		opts = append(opts, parser.WithTrailingCommas(policy))
+	}
+
+	if maxErrors != 1 {
+		opts = append(opts, parser.Recover(maxErrors))
+	}
+
+	return opts, nil
+}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
@@ -106,4 +285,15 @@ func init() {
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
+	rootCmd.Flags().BoolVar(&trace, "trace", false, "print the parser state for every token read")
+	rootCmd.Flags().IntVar(&maxDepth, "max-depth", parser.DefaultMaxDepth, "maximum nesting of objects and arrays (0 for no limit)")
+	rootCmd.Flags().IntVar(&maxErrors, "max-errors", 1, "report up to this many syntax errors instead of stopping at the first (0 for no limit)")
+	rootCmd.Flags().StringVar(&duplicateKeys, "duplicate-keys", "allow", "how to treat repeated object keys: allow, reject or warn")
+	rootCmd.Flags().StringVar(&trailingCommas, "trailing-commas", "", "how to treat a comma before '}' or ']': reject, allow or warn (default allow for json5, reject otherwise)")
+	rootCmd.Flags().BoolVar(&ndjson, "ndjson", false, "read newline-delimited JSON, checking each line as a separate document")
+	rootCmd.Flags().BoolVar(&skipBlank, "skip-blank", false, "with --ndjson, skip blank lines instead of reporting them as invalid")
+	rootCmd.Flags().BoolVar(&seq, "seq", false, "read a JSON text sequence (RFC 7464), checking each record as a separate document")
+	rootCmd.Flags().BoolVar(&strict, "strict", false, "reject invalid UTF-8 and whitespace other than space, tab, line feed and carriage return")
+	rootCmd.Flags().StringVar(&bom, "bom", "reject", "how to treat a leading UTF-8 byte order mark: reject or strip")
+	rootCmd.Flags().StringVar(&dialect, "dialect", "json", "syntax to accept: json, json5 (see https://spec.json5.org) or jsonc (JSON with comments)")
 }
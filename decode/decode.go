@@ -0,0 +1,457 @@
+// Package decode stores JSON documents in Go values, in the manner of
+// encoding/json's Unmarshal, using this module's lexer and parser.
+package decode
+
+import (
+	"encoding"
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/HrithikSawant/go-json-parser/lexer"
+	"github.com/HrithikSawant/go-json-parser/parser"
+)
+
+// Unmarshaler is implemented by types that decode their own JSON. It has
+// the same method as encoding/json's Unmarshaler, so existing
+// implementations work unchanged. UnmarshalJSON receives the value's input
+// bytes exactly as they appear in the document.
+type Unmarshaler interface {
+	UnmarshalJSON([]byte) error
+}
+
+// Unmarshal parses data and stores the value in the Go value v points to.
+//
+// Values are converted as by encoding/json: objects decode into structs,
+// honouring `json` field tags and embedded structs, or into maps; arrays
+// into slices and arrays; and any value into an empty interface as
+// map[string]any, []any, string, float64, bool or nil. Pointers are
+// allocated as needed, and types implementing Unmarshaler or
+// encoding.TextUnmarshaler decode themselves.
+//
+// A syntax error is returned as a *parser.ParseError. A value that does
+// not fit its Go type is reported as an *Error carrying its path and
+// position.
+func Unmarshal(data []byte, v any, opts ...Option) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("cannot unmarshal into %T: need a non-nil pointer", v)
+	}
+
+	root, err := parser.NewParser(lexer.NewBytesLexer(data)).ParseValue()
+	if err != nil {
+		return err
+	}
+
+	d := &decoder{data: data}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d.value(root, rv)
+}
+
+// decoder stores a document tree in Go values.
+type decoder struct {
+	data            []byte // the input, for Unmarshaler
+	disallowUnknown bool   // see DisallowUnknownFields
+	path            []string
+}
+
+// errorf returns an Error for the JSON value jv, which is being decoded
+// into a value of type t.
+func (d *decoder) errorf(jv parser.Value, t reflect.Type, format string, args ...any) *Error {
+	return &Error{
+		Path: "$" + strings.Join(d.path, ""),
+		Pos:  jv.Pos(),
+		Type: t,
+		Msg:  fmt.Sprintf(format, args...),
+	}
+}
+
+// mismatch returns the Error for a JSON value of the wrong kind for t.
+func (d *decoder) mismatch(jv parser.Value, t reflect.Type) *Error {
+	return d.errorf(jv, t, "cannot unmarshal %s into %s", jv.Kind(), t)
+}
+
+// raw returns the input bytes of jv.
+func (d *decoder) raw(jv parser.Value) []byte {
+	return d.data[jv.Pos().Offset:jv.End()]
+}
+
+// value stores jv in v.
+func (d *decoder) value(jv parser.Value, v reflect.Value) error {
+	u, tu, v := indirect(v, jv.Kind() == parser.KindNull)
+	if u != nil {
+		if err := u.UnmarshalJSON(d.raw(jv)); err != nil {
+			e := d.errorf(jv, reflect.TypeOf(u), "%v", err)
+			e.Err = err
+			return e
+		}
+		return nil
+	}
+	if tu != nil {
+		s, ok := jv.(*parser.String)
+		if !ok {
+			return d.mismatch(jv, reflect.TypeOf(tu))
+		}
+		if err := tu.UnmarshalText([]byte(s.Value)); err != nil {
+			e := d.errorf(jv, reflect.TypeOf(tu), "%v", err)
+			e.Err = err
+			return e
+		}
+		return nil
+	}
+
+	switch jv := jv.(type) {
+	case *parser.Null:
+		switch v.Kind() {
+		case reflect.Interface, reflect.Pointer, reflect.Map, reflect.Slice:
+			v.SetZero()
+		}
+		// Otherwise null leaves the value unchanged.
+		return nil
+	case *parser.Bool:
+		switch {
+		case v.Kind() == reflect.Bool:
+			v.SetBool(jv.Value)
+		case isEmptyInterface(v):
+			v.Set(reflect.ValueOf(jv.Value))
+		default:
+			return d.mismatch(jv, v.Type())
+		}
+		return nil
+	case *parser.String:
+		switch {
+		case v.Kind() == reflect.String:
+			v.SetString(jv.Value)
+		case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+			b, err := base64.StdEncoding.DecodeString(jv.Value)
+			if err != nil {
+				return d.errorf(jv, v.Type(), "invalid base64 data: %v", err)
+			}
+			v.SetBytes(b)
+		case isEmptyInterface(v):
+			v.Set(reflect.ValueOf(jv.Value))
+		default:
+			return d.mismatch(jv, v.Type())
+		}
+		return nil
+	case *parser.Number:
+		return d.number(jv, v)
+	case *parser.Array:
+		return d.array(jv, v)
+	case *parser.Object:
+		return d.object(jv, v)
+	}
+	return nil
+}
+
+// number stores n in v.
+func (d *decoder) number(n *parser.Number, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := n.Int64()
+		if err != nil || v.OverflowInt(i) {
+			return d.errorf(n, v.Type(), "cannot unmarshal number %s into %s", n.Literal, v.Type())
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := strconv.ParseUint(n.Literal, 10, 64)
+		if err != nil || v.OverflowUint(u) {
+			return d.errorf(n, v.Type(), "cannot unmarshal number %s into %s", n.Literal, v.Type())
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(n.Literal, v.Type().Bits())
+		if err != nil || v.OverflowFloat(f) {
+			return d.errorf(n, v.Type(), "cannot unmarshal number %s into %s", n.Literal, v.Type())
+		}
+		v.SetFloat(f)
+	default:
+		if !isEmptyInterface(v) {
+			return d.mismatch(n, v.Type())
+		}
+		f, err := n.Float64()
+		if err != nil {
+			return d.errorf(n, v.Type(), "cannot unmarshal number %s into float64", n.Literal)
+		}
+		v.Set(reflect.ValueOf(f))
+	}
+	return nil
+}
+
+// array stores a in v, which must be a slice, an array or an empty
+// interface.
+func (d *decoder) array(a *parser.Array, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Slice:
+		n := a.Len()
+		if v.IsNil() || v.Cap() < n {
+			v.Set(reflect.MakeSlice(v.Type(), n, n))
+		} else {
+			v.SetLen(n)
+		}
+	case reflect.Array:
+		// Extra elements are ignored and missing ones zeroed, as in
+		// encoding/json.
+		for i := a.Len(); i < v.Len(); i++ {
+			v.Index(i).SetZero()
+		}
+	default:
+		if !isEmptyInterface(v) {
+			return d.mismatch(a, v.Type())
+		}
+		v.Set(reflect.ValueOf(d.any(a)))
+		return nil
+	}
+
+	for i, e := range a.All() {
+		if i >= v.Len() {
+			break
+		}
+		d.path = append(d.path, "["+strconv.Itoa(i)+"]")
+		err := d.value(e, v.Index(i))
+		d.path = d.path[:len(d.path)-1]
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// object stores o in v, which must be a struct, a map with string or
+// integer keys, or an empty interface.
+func (d *decoder) object(o *parser.Object, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Struct:
+		return d.structFields(o, v)
+	case reflect.Map:
+		switch v.Type().Key().Kind() {
+		case reflect.String,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		default:
+			return d.mismatch(o, v.Type())
+		}
+	default:
+		if !isEmptyInterface(v) {
+			return d.mismatch(o, v.Type())
+		}
+		v.Set(reflect.ValueOf(d.any(o)))
+		return nil
+	}
+
+	if v.IsNil() {
+		v.Set(reflect.MakeMap(v.Type()))
+	}
+	kt, et := v.Type().Key(), v.Type().Elem()
+	for _, m := range o.Members {
+		d.path = append(d.path, keySegment(m.Key))
+		key := reflect.New(kt).Elem()
+		var err error
+		switch kt.Kind() {
+		case reflect.String:
+			key.SetString(m.Key)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			i, perr := strconv.ParseInt(m.Key, 10, 64)
+			if perr != nil || key.OverflowInt(i) {
+				err = d.errorf(m.Value, kt, "cannot use key %q as %s", m.Key, kt)
+			}
+			key.SetInt(i)
+		default:
+			u, perr := strconv.ParseUint(m.Key, 10, 64)
+			if perr != nil || key.OverflowUint(u) {
+				err = d.errorf(m.Value, kt, "cannot use key %q as %s", m.Key, kt)
+			}
+			key.SetUint(u)
+		}
+
+		elem := reflect.New(et).Elem()
+		if err == nil {
+			err = d.value(m.Value, elem)
+		}
+		d.path = d.path[:len(d.path)-1]
+		if err != nil {
+			return err
+		}
+		v.SetMapIndex(key, elem)
+	}
+	return nil
+}
+
+// structFields stores the members of o in the matching fields of the
+// struct v.
+func (d *decoder) structFields(o *parser.Object, v reflect.Value) error {
+	fields := cachedFields(v.Type())
+	for _, m := range o.Members {
+		d.path = append(d.path, keySegment(m.Key))
+		err := d.member(m, v, fields)
+		d.path = d.path[:len(d.path)-1]
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// member stores m in its field of the struct v.
+func (d *decoder) member(m parser.Member, v reflect.Value, fields []field) error {
+	f := lookup(fields, m.Key)
+	if f == nil {
+		if d.disallowUnknown {
+			err := d.errorf(m.Value, v.Type(), "unknown field %q in %s", m.Key, v.Type())
+			err.Pos = m.KeyPos
+			return err
+		}
+		return nil
+	}
+
+	fv := v
+	for i, x := range f.index {
+		if i > 0 && fv.Kind() == reflect.Pointer {
+			if fv.IsNil() {
+				if !fv.CanSet() {
+					return d.errorf(m.Value, fv.Type(), "cannot set embedded pointer to unexported struct %s", fv.Type().Elem())
+				}
+				fv.Set(reflect.New(fv.Type().Elem()))
+			}
+			fv = fv.Elem()
+		}
+		fv = fv.Field(x)
+	}
+
+	if f.quoted {
+		return d.quoted(m.Value, fv)
+	}
+	return d.value(m.Value, fv)
+}
+
+// quoted stores jv in v for a field with the ",string" option, where the
+// value is a JSON string holding the JSON encoding of a scalar.
+func (d *decoder) quoted(jv parser.Value, v reflect.Value) error {
+	s, ok := jv.(*parser.String)
+	if !ok {
+		if jv.Kind() == parser.KindNull {
+			return d.value(jv, v)
+		}
+		return d.errorf(jv, v.Type(), "invalid use of ,string struct tag, trying to unmarshal %s into %s", jv.Kind(), v.Type())
+	}
+
+	inner, err := parser.NewParser(lexer.NewLexer(s.Value)).ParseValue()
+	if err != nil || inner.Kind() == parser.KindArray || inner.Kind() == parser.KindObject {
+		return d.errorf(jv, v.Type(), "invalid use of ,string struct tag, trying to unmarshal %q into %s", s.Value, v.Type())
+	}
+
+	// The inner value's positions are relative to the string's contents.
+	data := d.data
+	d.data = []byte(s.Value)
+	defer func() { d.data = data }()
+
+	if err := d.value(inner, v); err != nil {
+		if e, ok := err.(*Error); ok {
+			e.Pos = jv.Pos()
+		}
+		return err
+	}
+	return nil
+}
+
+// any returns jv as the value encoding/json would store in an empty
+// interface.
+func (d *decoder) any(jv parser.Value) any {
+	switch jv := jv.(type) {
+	case *parser.Object:
+		m := make(map[string]any, jv.Len())
+		for _, mem := range jv.Members {
+			m[mem.Key] = d.any(mem.Value)
+		}
+		return m
+	case *parser.Array:
+		s := make([]any, 0, jv.Len())
+		for _, e := range jv.All() {
+			s = append(s, d.any(e))
+		}
+		return s
+	case *parser.String:
+		return jv.Value
+	case *parser.Number:
+		f, _ := jv.Float64()
+		return f
+	case *parser.Bool:
+		return jv.Value
+	}
+	return nil
+}
+
+// indirect walks down v, allocating pointers as needed, until it reaches a
+// value that is not a pointer. It stops early at a value implementing
+// Unmarshaler or encoding.TextUnmarshaler, and, when decoding null, at the
+// last pointer so that it can be set to nil.
+func indirect(v reflect.Value, decodingNull bool) (Unmarshaler, encoding.TextUnmarshaler, reflect.Value) {
+	// Methods with pointer receivers are only found through the address
+	// of a named value.
+	v0 := v
+	haveAddr := false
+	if v.Kind() != reflect.Pointer && v.Type().Name() != "" && v.CanAddr() {
+		haveAddr = true
+		v = v.Addr()
+	}
+
+	for {
+		// Decode into the value an interface holds if it is a non-nil
+		// pointer.
+		if v.Kind() == reflect.Interface && !v.IsNil() {
+			e := v.Elem()
+			if e.Kind() == reflect.Pointer && !e.IsNil() && (!decodingNull || e.Elem().Kind() == reflect.Pointer) {
+				haveAddr = false
+				v = e
+				continue
+			}
+		}
+		if v.Kind() != reflect.Pointer {
+			break
+		}
+		if decodingNull && v.CanSet() {
+			break
+		}
+
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		if v.Type().NumMethod() > 0 && v.CanInterface() {
+			if u, ok := v.Interface().(Unmarshaler); ok {
+				return u, nil, reflect.Value{}
+			}
+			if !decodingNull {
+				if tu, ok := v.Interface().(encoding.TextUnmarshaler); ok {
+					return nil, tu, reflect.Value{}
+				}
+			}
+		}
+
+		if haveAddr {
+			v = v0
+			haveAddr = false
+		} else {
+			v = v.Elem()
+		}
+	}
+	return nil, nil, v
+}
+
+// isEmptyInterface reports whether v is of type any.
+func isEmptyInterface(v reflect.Value) bool {
+	return v.Kind() == reflect.Interface && v.NumMethod() == 0
+}
+
+// keySegment returns the path segment for the object key key.
+func keySegment(key string) string {
+	if key != "" && strings.IndexFunc(key, func(r rune) bool {
+		return !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	}) < 0 {
+		return "." + key
+	}
+	return "[" + strconv.Quote(key) + "]"
+}
@@ -0,0 +1,222 @@
+package decode
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HrithikSawant/go-json-parser/parser"
+)
+
+type Address struct {
+	City string `json:"city"`
+	Zip  string `json:"zip,omitempty"`
+}
+
+type Base struct {
+	ID      int    `json:"id"`
+	Created string `json:"created"`
+}
+
+type Person struct {
+	Base
+	*Extra
+	Name     string            `json:"name"`
+	Age      uint8             `json:"age"`
+	Score    float64           `json:"score"`
+	Admin    bool              `json:"admin"`
+	Secret   string            `json:"-"`
+	Dash     string            `json:"-,"`
+	Count    int64             `json:"count,string"`
+	Address  *Address          `json:"address"`
+	Tags     []string          `json:"tags"`
+	Pair     [2]int            `json:"pair"`
+	Labels   map[string]int    `json:"labels"`
+	ByID     map[int]string    `json:"by_id"`
+	Extra1   any               `json:"extra1"`
+	Data     []byte            `json:"data"`
+	When     time.Time         `json:"when"`
+	Nums     map[string]*Value `json:"nums"`
+	Untagged string
+}
+
+type Extra struct {
+	Note string `json:"note"`
+}
+
+// Value keeps the input its UnmarshalJSON is given.
+type Value struct {
+	Raw string
+}
+
+func (v *Value) UnmarshalJSON(b []byte) error {
+	v.Raw = string(b)
+	return nil
+}
+
+func TestUnmarshal_Struct(t *testing.T) {
+	input := `{
+		"id": 7, "created": "today", "note": "embedded pointer",
+		"name": "Alice", "age": 30, "score": 9.5, "admin": true,
+		"Secret": "x", "-": "dash", "count": "42",
+		"address": {"city": "Pune"},
+		"tags": ["a", "b"], "pair": [1, 2, 3],
+		"labels": {"x": 1}, "by_id": {"5": "five"},
+		"extra1": {"k": [1, "two", null, false]},
+		"untagged": "case-insensitive",
+		"data": "aGk=",
+		"when": "2024-05-01T10:00:00Z",
+		"nums": {"a": [1,  2], "b": null}
+	}`
+
+	var p Person
+	if err := Unmarshal([]byte(input), &p); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	expected := Person{
+		Base:     Base{ID: 7, Created: "today"},
+		Extra:    &Extra{Note: "embedded pointer"},
+		Name:     "Alice",
+		Age:      30,
+		Score:    9.5,
+		Admin:    true,
+		Dash:     "dash",
+		Count:    42,
+		Address:  &Address{City: "Pune"},
+		Tags:     []string{"a", "b"},
+		Pair:     [2]int{1, 2},
+		Labels:   map[string]int{"x": 1},
+		ByID:     map[int]string{5: "five"},
+		Extra1:   map[string]any{"k": []any{1.0, "two", nil, false}},
+		Untagged: "case-insensitive",
+		Data:     []byte("hi"),
+		When:     time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		Nums:     map[string]*Value{"a": {Raw: "[1,  2]"}, "b": nil},
+	}
+	if !reflect.DeepEqual(p, expected) {
+		t.Errorf("Unexpected result:\n got %+v\nwant %+v", p, expected)
+	}
+}
+
+func TestUnmarshal_Values(t *testing.T) {
+	var s []any
+	if err := Unmarshal([]byte(`[1, "x", true, null, {}, []]`), &s); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if expected := []any{1.0, "x", true, nil, map[string]any{}, []any{}}; !reflect.DeepEqual(s, expected) {
+		t.Errorf("Got %#v, expected %#v", s, expected)
+	}
+
+	// null clears pointers, slices and maps but leaves other values alone.
+	n := 5
+	ptr := &n
+	num := 3
+	if err := Unmarshal([]byte(`null`), &ptr); err != nil || ptr != nil {
+		t.Errorf("Expected null to clear the pointer, got %v, %v", ptr, err)
+	}
+	if err := Unmarshal([]byte(`null`), &num); err != nil || num != 3 {
+		t.Errorf("Expected null to leave the int alone, got %v, %v", num, err)
+	}
+
+	// A non-nil pointer in an interface is decoded into.
+	var target int
+	var iface any = &target
+	if err := Unmarshal([]byte(`12`), &iface); err != nil || target != 12 {
+		t.Errorf("Expected to decode through the interface, got %v, %v", target, err)
+	}
+}
+
+func TestUnmarshal_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		target any
+		path   string
+		offset int
+		msg    string
+	}{
+		{"TypeMismatch", `{"name": 5}`, &Person{}, "$.name", 9, "cannot unmarshal number into string"},
+		{"Overflow", `{"age": 300}`, &Person{}, "$.age", 8, "cannot unmarshal number 300 into uint8"},
+		{"Fraction", `{"id": 1.5}`, &Person{}, "$.id", 7, "cannot unmarshal number 1.5 into int"},
+		{"Nested", `{"tags": ["a", 2]}`, &Person{}, "$.tags[1]", 15, "cannot unmarshal number into string"},
+		{"MapKey", `{"by_id": {"x": "y"}}`, &Person{}, `$.by_id.x`, 16, `cannot use key "x" as int`},
+		{"OddKey", `{"labels": {"a b": "c"}}`, &Person{}, `$.labels["a b"]`, 19, "cannot unmarshal string into int"},
+		{"Quoted", `{"count": 42}`, &Person{}, "$.count", 10, "invalid use of ,string struct tag, trying to unmarshal number into int64"},
+		{"TextUnmarshaler", `{"when": "soon"}`, &Person{}, "$.when", 9, `parsing time "soon" as "2006-01-02T15:04:05Z07:00": cannot parse "soon" as "2006"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Unmarshal([]byte(tt.input), tt.target)
+			var derr *Error
+			if !errors.As(err, &derr) {
+				t.Fatalf("Expected an *Error, got %v", err)
+			}
+			if derr.Path != tt.path || derr.Pos.Offset != tt.offset || derr.Msg != tt.msg {
+				t.Errorf("Got (%s, %d, %q), expected (%s, %d, %q)", derr.Path, derr.Pos.Offset, derr.Msg, tt.path, tt.offset, tt.msg)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_DisallowUnknownFields(t *testing.T) {
+	input := []byte(`{"city": "Pune", "country": "IN"}`)
+
+	var a Address
+	if err := Unmarshal(input, &a); err != nil || a.City != "Pune" {
+		t.Fatalf("Expected unknown fields to be ignored, got %+v, %v", a, err)
+	}
+
+	err := Unmarshal(input, &a, DisallowUnknownFields())
+	var derr *Error
+	if !errors.As(err, &derr) || derr.Path != "$.country" {
+		t.Fatalf("Expected an error at $.country, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "line 1, column 18") {
+		t.Errorf("Expected the position in %q", err)
+	}
+}
+
+func TestUnmarshal_SyntaxError(t *testing.T) {
+	var v any
+	err := Unmarshal([]byte(`{"a": }`), &v)
+	var perr *parser.ParseError
+	if !errors.As(err, &perr) {
+		t.Errorf("Expected a *parser.ParseError, got %v", err)
+	}
+}
+
+func TestUnmarshal_NonPointer(t *testing.T) {
+	var v Address
+	if err := Unmarshal([]byte(`{}`), v); err == nil {
+		t.Error("Expected an error for a non-pointer")
+	}
+	if err := Unmarshal([]byte(`{}`), (*Address)(nil)); err == nil {
+		t.Error("Expected an error for a nil pointer")
+	}
+}
+
+// Ambiguous fields at the same depth are dropped unless one is tagged.
+func TestTypeFields_Embedding(t *testing.T) {
+	type A struct{ Name, Both string }
+	type B struct {
+		Name string
+		Both string `json:"Both"`
+	}
+	type C struct {
+		A
+		B
+		Own string `json:"Name2"`
+	}
+
+	var names []string
+	for _, f := range typeFields(reflect.TypeOf(C{})) {
+		names = append(names, f.name)
+	}
+	if expected := []string{"Both", "Name2"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("Got fields %v, expected %v", names, expected)
+	}
+}
@@ -0,0 +1,27 @@
+package decode
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/HrithikSawant/go-json-parser/lexer"
+)
+
+// Error describes a JSON value that cannot be stored in the Go value given
+// to Unmarshal, or an object key that matches no field under
+// DisallowUnknownFields.
+type Error struct {
+	Path string         // where the value is in the document, such as $.users[2].name
+	Pos  lexer.Position // where the value starts in the input
+	Type reflect.Type   // the Go type the value was decoded into, if known
+	Msg  string
+	Err  error // the error returned by an Unmarshaler, if any
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s: %s", e.Pos, e.Path, e.Msg)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
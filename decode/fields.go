@@ -0,0 +1,158 @@
+package decode
+
+import (
+	"cmp"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// field is a struct field that a JSON object member can be decoded into.
+type field struct {
+	name   string
+	index  []int // for reflect.Value.FieldByIndex, through embedded structs
+	tagged bool  // the name comes from a json tag
+	quoted bool  // the ",string" option: the value is wrapped in a JSON string
+}
+
+var fieldCache sync.Map // map[reflect.Type][]field
+
+// cachedFields returns the fields of the struct type t.
+func cachedFields(t reflect.Type) []field {
+	if f, ok := fieldCache.Load(t); ok {
+		return f.([]field)
+	}
+	f, _ := fieldCache.LoadOrStore(t, typeFields(t))
+	return f.([]field)
+}
+
+// typeFields lists the fields of t by their JSON names, including the
+// fields promoted from embedded structs. As in Go, a field hides the fields
+// of the same name at deeper levels; of fields at the same level, one with
+// a json tag wins, and if that leaves more than one, none of them is used.
+func typeFields(t reflect.Type) []field {
+	type embedded struct {
+		typ   reflect.Type
+		index []int
+	}
+
+	var all []field
+	visited := map[reflect.Type]bool{}
+	for level := []embedded{{typ: t}}; len(level) > 0; {
+		var next []embedded
+		for _, e := range level {
+			if visited[e.typ] {
+				continue
+			}
+			visited[e.typ] = true
+
+			for i := range e.typ.NumField() {
+				sf := e.typ.Field(i)
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, opts, _ := strings.Cut(tag, ",")
+				index := append(e.index[:len(e.index):len(e.index)], i)
+
+				ft := sf.Type
+				if sf.Anonymous {
+					if ft.Kind() == reflect.Pointer {
+						ft = ft.Elem()
+					}
+					if name == "" && ft.Kind() == reflect.Struct {
+						next = append(next, embedded{typ: ft, index: index})
+						continue
+					}
+				}
+				if !sf.IsExported() {
+					continue
+				}
+
+				f := field{name: name, index: index, tagged: name != ""}
+				if f.name == "" {
+					f.name = sf.Name
+				}
+				f.quoted = hasOption(opts, "string") && quotable(ft)
+				all = append(all, f)
+			}
+		}
+		level = next
+	}
+
+	// Sort each name's fields shallowest first, tagged before untagged, and
+	// keep the first if it dominates the second.
+	slices.SortStableFunc(all, func(a, b field) int {
+		if c := strings.Compare(a.name, b.name); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(len(a.index), len(b.index)); c != 0 {
+			return c
+		}
+		if a.tagged != b.tagged {
+			if a.tagged {
+				return -1
+			}
+			return 1
+		}
+		return 0
+	})
+
+	var fields []field
+	for i := 0; i < len(all); {
+		j := i + 1
+		for j < len(all) && all[j].name == all[i].name {
+			j++
+		}
+		if j == i+1 || len(all[i].index) < len(all[i+1].index) || all[i].tagged && !all[i+1].tagged {
+			fields = append(fields, all[i])
+		}
+		i = j
+	}
+	return fields
+}
+
+// lookup finds the field for an object key, preferring an exact match and
+// falling back to a case-insensitive one, as encoding/json does.
+func lookup(fields []field, key string) *field {
+	var fold *field
+	for i := range fields {
+		if fields[i].name == key {
+			return &fields[i]
+		}
+		if fold == nil && strings.EqualFold(fields[i].name, key) {
+			fold = &fields[i]
+		}
+	}
+	return fold
+}
+
+// hasOption reports whether the comma-separated tag options opts include
+// name.
+func hasOption(opts, name string) bool {
+	for opts != "" {
+		var opt string
+		opt, opts, _ = strings.Cut(opts, ",")
+		if opt == name {
+			return true
+		}
+	}
+	return false
+}
+
+// quotable reports whether the ",string" option applies to fields of type
+// t.
+func quotable(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,13 @@
+package decode
+
+// Option configures Unmarshal.
+type Option func(*decoder)
+
+// DisallowUnknownFields makes Unmarshal fail when an object has a key that
+// matches no field of the struct it is decoded into. By default such keys
+// are ignored.
+func DisallowUnknownFields() Option {
+	return func(d *decoder) {
+		d.disallowUnknown = true
+	}
+}
@@ -0,0 +1,60 @@
+package lexer
+
+import "unicode/utf8"
+
+// scanComment reads the JSON5 or JSONC comment at l.pos as a COMMENT
+// token for a lexer that keeps comments, or an INVALID one if it is not
+// closed. It reports false if there is none.
+func (l *Lexer) scanComment() (Token, bool) {
+	ok, err := l.skipComment()
+	if err != nil {
+		return l.invalid(err), true
+	}
+	if !ok {
+		return Token{}, false
+	}
+	return Token{Type: TokenComment, Literal: l.literal(l.start, l.pos)}, true
+}
+
+// skipComment skips the // or /* comment at l.pos and reports whether
+// there was one.
+func (l *Lexer) skipComment() (bool, *Error) {
+	if !l.ensure(2) {
+		return false, nil
+	}
+	switch l.buf[l.pos+1] {
+	case '/':
+		l.pos += 2
+		for l.more() && l.buf[l.pos] != '\n' && l.buf[l.pos] != '\r' {
+			if err := l.skipCommentChar(); err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	case '*':
+		l.pos += 2
+		for l.ensure(2) {
+			if l.buf[l.pos] == '*' && l.buf[l.pos+1] == '/' {
+				l.pos += 2
+				return true, nil
+			}
+			if err := l.skipCommentChar(); err != nil {
+				return false, err
+			}
+		}
+		l.pos = len(l.buf)
+		return false, l.errorAt(ReasonUnterminatedComment, l.start, l.pos, "unterminated block comment: expected '*/' before the end of the input")
+	}
+	return false, nil
+}
+
+// skipCommentChar skips the character at l.pos inside a comment. Under
+// Strict it must be valid UTF-8, as in the rest of the input.
+func (l *Lexer) skipCommentChar() *Error {
+	if l.strict && l.buf[l.pos] >= utf8.RuneSelf {
+		_, err := l.scanRune()
+		return err
+	}
+	l.pos++
+	return nil
+}
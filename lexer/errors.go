@@ -0,0 +1,58 @@
+package lexer
+
+import "fmt"
+
+// Reason is a stable code for why the lexer rejected its input.
+type Reason int
+
+const (
+	ReasonNone                Reason = iota // No error
+	ReasonUnterminatedString                // A string is missing its closing quote
+	ReasonBadEscape                         // A backslash escape is not one JSON defines
+	ReasonBadNumber                         // A number does not follow the JSON grammar
+	ReasonUnknownLiteral                    // A bare word other than true, false or null
+	ReasonControlChar                       // An unescaped control character in a string
+	ReasonInvalidUTF8                       // Input that is not valid UTF-8, see Strict
+	ReasonUnexpectedChar                    // A character that cannot start a token
+	ReasonBOM                               // A byte order mark, see WithBOM
+	ReasonIO                                // Reading the input failed
+	ReasonUnterminatedComment               // A block comment is missing its closing */ (JSON5, JSONC)
+)
+
+var reasonNames = [...]string{
+	ReasonNone:                "none",
+	ReasonUnterminatedString:  "unterminated string",
+	ReasonBadEscape:           "bad escape",
+	ReasonBadNumber:           "bad number",
+	ReasonUnknownLiteral:      "unknown literal",
+	ReasonControlChar:         "control character",
+	ReasonInvalidUTF8:         "invalid UTF-8",
+	ReasonUnexpectedChar:      "unexpected character",
+	ReasonBOM:                 "byte order mark",
+	ReasonIO:                  "read error",
+	ReasonUnterminatedComment: "unterminated comment",
+}
+
+func (r Reason) String() string {
+	if r < 0 || int(r) >= len(reasonNames) {
+		return "unknown"
+	}
+	return reasonNames[r]
+}
+
+// Error is a lexical error. It is set on the Err field of INVALID tokens.
+type Error struct {
+	Reason Reason
+	Pos    Position // start of the offending text
+	End    int      // byte offset just past the offending text
+	Msg    string   // human-readable description
+	Err    error    // the underlying read error for ReasonIO
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Msg)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,204 @@
+package lexer
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// skipUnicodeSpace skips the non-ASCII character at l.pos if JSON5 treats
+// it as whitespace, and reports whether it did.
+func (l *Lexer) skipUnicodeSpace() bool {
+	for !utf8.FullRune(l.buf[l.pos:]) && l.fill() {
+	}
+	r, size := utf8.DecodeRune(l.buf[l.pos:])
+	if unicode.Is(unicode.Zs, r) || r == '\u2028' || r == '\u2029' || r == '\uFEFF' {
+		l.pos += size
+		return true
+	}
+	return false
+}
+
+// identStart reports whether a JSON5 identifier starts at l.pos.
+func (l *Lexer) identStart() bool {
+	ch := l.buf[l.pos]
+	if ch < utf8.RuneSelf {
+		return isAlpha(ch) || ch == '_' || ch == '$' || ch == '\\'
+	}
+	for !utf8.FullRune(l.buf[l.pos:]) && l.fill() {
+	}
+	r, _ := utf8.DecodeRune(l.buf[l.pos:])
+	return isIdentRune(r, true)
+}
+
+// isIdentRune reports whether the non-ASCII r may appear in an identifier,
+// at its start if first is set.
+func isIdentRune(r rune, first bool) bool {
+	if unicode.IsLetter(r) || unicode.Is(unicode.Nl, r) {
+		return true
+	}
+	return !first && (unicode.In(r, unicode.Mn, unicode.Mc, unicode.Nd, unicode.Pc) || r == '\u200C' || r == '\u200D')
+}
+
+// scanIdentifier reads a JSON5 identifier: an unquoted object key such as
+// name or $_x1, which may contain \u escapes, or one of the literals true,
+// false, null, Infinity and NaN.
+func (l *Lexer) scanIdentifier() Token {
+scan:
+	for l.more() {
+		ch := l.buf[l.pos]
+		switch {
+		case isAlpha(ch) || isDigit(ch) || ch == '_' || ch == '$':
+			l.pos++
+		case ch == '\\':
+			from := l.base + l.pos
+			l.pos++
+			if !l.more() || l.buf[l.pos] != 'u' {
+				return l.invalid(l.errorAt(ReasonBadEscape, from-l.base, l.pos, "invalid escape sequence in identifier: expected '\\u'"))
+			}
+			l.pos++
+			if err := l.skipHex(from, 4, "four"); err != nil {
+				return l.invalid(err)
+			}
+		case ch >= utf8.RuneSelf:
+			at := l.base + l.pos // absolute, as filling may move buf
+			r, err := l.scanRune()
+			if err != nil {
+				return l.invalid(err)
+			}
+			if !isIdentRune(r, at == l.base+l.start) {
+				l.pos = at - l.base
+				break scan
+			}
+		default:
+			break scan
+		}
+	}
+
+	switch string(l.buf[l.start:l.pos]) {
+	case "true":
+		return Token{Type: TokenBool, Literal: "true"}
+	case "false":
+		return Token{Type: TokenBool, Literal: "false"}
+	case "null":
+		return Token{Type: TokenNull, Literal: "null"}
+	case "Infinity", "NaN":
+		return Token{Type: TokenNumber, Literal: l.literal(l.start, l.pos), Number: NumberFloat}
+	}
+	return Token{Type: TokenIdentifier, Literal: l.literal(l.start, l.pos)}
+}
+
+// scanNumber5 reads a JSON5 number: a JSON number that may also have a
+// leading '+', a decimal point with no digits before or after it, or be
+// hexadecimal, Infinity or NaN.
+func (l *Lexer) scanNumber5() Token {
+	kind := NumberInteger
+
+	if ch := l.buf[l.pos]; ch == '+' || ch == '-' {
+		l.pos++
+		if !l.more() {
+			return l.invalidNumber("expected digit after '" + string(ch) + "'")
+		}
+		if isAlpha(l.buf[l.pos]) {
+			for l.more() && isAlpha(l.buf[l.pos]) {
+				l.pos++
+			}
+			if word := string(l.buf[l.start+1 : l.pos]); word == "Infinity" || word == "NaN" {
+				return Token{Type: TokenNumber, Literal: l.literal(l.start, l.pos), Number: NumberFloat}
+			}
+			return l.invalidNumber("expected digit, Infinity or NaN after '" + string(ch) + "'")
+		}
+	}
+
+	// Hexadecimal
+	if l.ensure(2) && l.buf[l.pos] == '0' && (l.buf[l.pos+1] == 'x' || l.buf[l.pos+1] == 'X') {
+		l.pos += 2
+		if !l.more() || !isHexDigit(l.buf[l.pos]) {
+			return l.invalidNumber("expected hex digit after '0x'")
+		}
+		for l.more() && isHexDigit(l.buf[l.pos]) {
+			l.pos++
+		}
+		return Token{Type: TokenNumber, Literal: l.literal(l.start, l.pos), Number: NumberInteger}
+	}
+
+	// Integer part, which may be left out before a fraction
+	intDigits := l.more() && isDigit(l.buf[l.pos])
+	switch {
+	case !intDigits:
+		if !l.more() || l.buf[l.pos] != '.' {
+			return l.invalidNumber("expected digit")
+		}
+	case l.buf[l.pos] == '0':
+		l.pos++
+		if l.more() && isDigit(l.buf[l.pos]) {
+			l.skipDigits()
+			return l.invalidNumber("leading zeros are not allowed")
+		}
+	default:
+		l.skipDigits()
+	}
+
+	// Fractional part, which may be left out after the decimal point
+	if l.more() && l.buf[l.pos] == '.' {
+		kind = NumberFloat
+		l.pos++
+		if l.more() && isDigit(l.buf[l.pos]) {
+			l.skipDigits()
+		} else if !intDigits {
+			return l.invalidNumber("expected digit after decimal point")
+		}
+	}
+
+	// Exponent part
+	if l.more() && (l.buf[l.pos] == 'e' || l.buf[l.pos] == 'E') {
+		kind = NumberFloat
+		l.pos++
+		if l.more() && (l.buf[l.pos] == '+' || l.buf[l.pos] == '-') {
+			l.pos++
+		}
+		if !l.more() || !isDigit(l.buf[l.pos]) {
+			return l.invalidNumber("expected digit in exponent")
+		}
+		l.skipDigits()
+	}
+
+	return Token{Type: TokenNumber, Literal: l.literal(l.start, l.pos), Number: kind}
+}
+
+// scanEscape5 reads the JSON5 escape sequence whose backslash is at the
+// input offset from. Besides the JSON escapes, JSON5 has \' \v \0 and
+// \xHH, a backslash before a line break continues the string on the next
+// line, and a backslash before any other character but a digit stands
+// for that character.
+func (l *Lexer) scanEscape5(from int) *Error {
+	ch := l.buf[l.pos]
+	switch {
+	case ch == 'u':
+		l.pos++
+		return l.skipHex(from, 4, "four")
+	case ch == 'x':
+		l.pos++
+		return l.skipHex(from, 2, "two")
+	case ch == '0':
+		l.pos++
+		if l.more() && isDigit(l.buf[l.pos]) {
+			l.pos++
+			return l.errorAt(ReasonBadEscape, from-l.base, l.pos, "invalid escape sequence '%s' in string: '\\0' must not be followed by a digit", l.buf[from-l.base:l.pos])
+		}
+	case isDigit(ch):
+		l.pos++
+		return l.errorAt(ReasonBadEscape, from-l.base, l.pos, "invalid escape sequence '\\%c' in string", ch)
+	case ch == '\r':
+		l.pos++
+		if l.more() && l.buf[l.pos] == '\n' {
+			l.pos++
+		}
+	case ch >= utf8.RuneSelf:
+		if _, err := l.scanRune(); err != nil {
+			return err
+		}
+	default:
+		l.pos++
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package lexer
+
+import (
+	"testing"
+)
+
+func TestNextToken_JSON5(t *testing.T) {
+	input := `{
+		// a comment
+		unquoted: 'single "quoted"', /* block
+		comment */ $_id1: 0x1F,
+		half: .5, whole: 5., plus: +1, neg: -Infinity, nan: NaN,
+	}`
+	expectedTokens := []Token{
+		{Type: TokenCurlyOpen, Literal: "{"},
+		{Type: TokenIdentifier, Literal: "unquoted"},
+		{Type: TokenColon, Literal: ":"},
+		{Type: TokenString, Literal: `single "quoted"`},
+		{Type: TokenComma, Literal: ","},
+		{Type: TokenIdentifier, Literal: "$_id1"},
+		{Type: TokenColon, Literal: ":"},
+		{Type: TokenNumber, Literal: "0x1F", Number: NumberInteger},
+		{Type: TokenComma, Literal: ","},
+		{Type: TokenIdentifier, Literal: "half"},
+		{Type: TokenColon, Literal: ":"},
+		{Type: TokenNumber, Literal: ".5", Number: NumberFloat},
+		{Type: TokenComma, Literal: ","},
+		{Type: TokenIdentifier, Literal: "whole"},
+		{Type: TokenColon, Literal: ":"},
+		{Type: TokenNumber, Literal: "5.", Number: NumberFloat},
+		{Type: TokenComma, Literal: ","},
+		{Type: TokenIdentifier, Literal: "plus"},
+		{Type: TokenColon, Literal: ":"},
+		{Type: TokenNumber, Literal: "+1", Number: NumberInteger},
+		{Type: TokenComma, Literal: ","},
+		{Type: TokenIdentifier, Literal: "neg"},
+		{Type: TokenColon, Literal: ":"},
+		{Type: TokenNumber, Literal: "-Infinity", Number: NumberFloat},
+		{Type: TokenComma, Literal: ","},
+		{Type: TokenIdentifier, Literal: "nan"},
+		{Type: TokenColon, Literal: ":"},
+		{Type: TokenNumber, Literal: "NaN", Number: NumberFloat},
+		{Type: TokenComma, Literal: ","},
+		{Type: TokenCurlyClose, Literal: "}"},
+		{Type: TokenEOF, Literal: ""},
+	}
+
+	lex := NewLexer(input, WithDialect(DialectJSON5))
+	for i, expected := range expectedTokens {
+		tok := lex.NextToken()
+		if tok.Type != expected.Type || tok.Literal != expected.Literal || tok.Number != expected.Number {
+			t.Errorf("Token %d - got (%q, %q, %v), expected (%q, %q, %v)", i, tok.Type, tok.Literal, tok.Number, expected.Type, expected.Literal, expected.Number)
+		}
+	}
+}
+
+func TestNextToken_JSON5Strings(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`'it\'s'`, `it\'s`},
+		{"'line \\\ncontinued'", "line \\\ncontinued"},
+		{`"tab	inside"`, "tab\tinside"},
+		{`'\x41\0\v'`, `\x41\0\v`},
+		{"'a\\ b'", "a\\ b"},
+	}
+
+	for _, tt := range tests {
+		tok := NewLexer(tt.input, WithDialect(DialectJSON5)).NextToken()
+		if tok.Type != TokenString || tok.Literal != tt.expected {
+			t.Errorf("Input %q - got (%q, %q), expected (STRING, %q)", tt.input, tok.Type, tok.Literal, tt.expected)
+		}
+	}
+}
+
+func TestNextToken_JSON5Invalid(t *testing.T) {
+	tests := []struct {
+		input  string
+		reason Reason
+	}{
+		{`/* never closed`, ReasonUnterminatedComment},
+		{"'line\nbreak'", ReasonControlChar},
+		{`'\1'`, ReasonBadEscape},
+		{`'\x4'`, ReasonBadEscape},
+		{`0x`, ReasonBadNumber},
+		{`+Inf`, ReasonBadNumber},
+		{`.`, ReasonBadNumber},
+		{`007`, ReasonBadNumber},
+	}
+
+	for _, tt := range tests {
+		tok := NewLexer(tt.input, WithDialect(DialectJSON5)).NextToken()
+		if tok.Type != TokenInvalid || tok.Err == nil || tok.Err.Reason != tt.reason {
+			t.Errorf("Input %q - got (%q, %v), expected %v", tt.input, tok.Type, tok.Err, tt.reason)
+		}
+	}
+}
+
+func TestNextToken_JSON5Whitespace(t *testing.T) {
+	input := "\u00a0\ufeff[\u3000x\u2028]"
+	expected := []TokenType{TokenSquareOpen, TokenIdentifier, TokenSquareClose, TokenEOF}
+
+	lex := NewLexer(input, WithDialect(DialectJSON5))
+	for i, typ := range expected {
+		if tok := lex.NextToken(); tok.Type != typ {
+			t.Errorf("Token %d - got %q, expected %q", i, tok.Type, typ)
+		}
+	}
+}
+
+// Strict keeps the whitespace JSON5 defines, but not in JSON or JSONC.
+func TestNextToken_JSON5Strict(t *testing.T) {
+	input := "{a:\v1,\fb:\u00a02\ufeff}"
+	expected := []TokenType{
+		TokenCurlyOpen, TokenIdentifier, TokenColon, TokenNumber, TokenComma,
+		TokenIdentifier, TokenColon, TokenNumber, TokenCurlyClose, TokenEOF,
+	}
+
+	lex := NewLexer(input, WithDialect(DialectJSON5), Strict())
+	for i, typ := range expected {
+		if tok := lex.NextToken(); tok.Type != typ {
+			t.Errorf("Token %d - got (%q, %v), expected %q", i, tok.Type, tok.Err, typ)
+		}
+	}
+
+	if tok := NewLexer("\v1", Strict()).NextToken(); tok.Type != TokenInvalid {
+		t.Errorf("Expected strict JSON to reject a vertical tab, got %q", tok.Type)
+	}
+	for _, input := range []string{"{\v}", "{\f}"} {
+		lex := NewLexer(input, WithDialect(DialectJSONC), Strict())
+		lex.NextToken()
+		if tok := lex.NextToken(); tok.Type != TokenInvalid {
+			t.Errorf("Expected strict JSONC to reject %q, got %q", input, tok.Type)
+		}
+	}
+}
+
+// The JSON5 extensions stay invalid in the default dialect.
+func TestNextToken_JSON5OnlyInDialect(t *testing.T) {
+	for _, input := range []string{`key`, `'single'`, `.5`, `+1`, `// comment`, `Infinity`} {
+		if tok := NewLexer(input).NextToken(); tok.Type != TokenInvalid {
+			t.Errorf("Input %q - got %q, expected INVALID", input, tok.Type)
+		}
+	}
+}
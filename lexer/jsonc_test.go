@@ -0,0 +1,110 @@
+package lexer
+
+import (
+	"testing"
+)
+
+func TestNextToken_JSONC(t *testing.T) {
+	input := "{\n  // line\n  \"a\": 1 /* block */\n}"
+	expected := []TokenType{TokenCurlyOpen, TokenString, TokenColon, TokenNumber, TokenCurlyClose, TokenEOF}
+
+	lex := NewLexer(input, WithDialect(DialectJSONC))
+	for i, typ := range expected {
+		if tok := lex.NextToken(); tok.Type != typ {
+			t.Errorf("Token %d - got %q, expected %q", i, tok.Type, typ)
+		}
+	}
+
+	// Only comments are added to JSON.
+	for _, input := range []string{`'single'`, `key`, `+1`, `/ 1`} {
+		if tok := NewLexer(input, WithDialect(DialectJSONC)).NextToken(); tok.Type != TokenInvalid {
+			t.Errorf("Input %q - got %q, expected INVALID", input, tok.Type)
+		}
+	}
+}
+
+func TestNextToken_KeepComments(t *testing.T) {
+	input := "[1, // one\n/* two */ 2]"
+	expected := []struct {
+		typ     TokenType
+		literal string
+		line    int
+		column  int
+	}{
+		{TokenSquareOpen, "[", 1, 1},
+		{TokenNumber, "1", 1, 2},
+		{TokenComma, ",", 1, 3},
+		{TokenComment, "// one", 1, 5},
+		{TokenComment, "/* two */", 2, 1},
+		{TokenNumber, "2", 2, 11},
+		{TokenSquareClose, "]", 2, 12},
+		{TokenEOF, "", 2, 13},
+	}
+
+	for _, lex := range []*Lexer{
+		NewLexer(input, WithDialect(DialectJSONC), KeepComments()),
+		NewBytesLexer([]byte(input), WithDialect(DialectJSONC), KeepComments()),
+	} {
+		for i, want := range expected {
+			tok := lex.NextToken()
+			if tok.Type != want.typ || lex.Text(tok) != want.literal || tok.Pos.Line != want.line || tok.Pos.Column != want.column {
+				t.Errorf("Token %d - got (%q, %q, %s), expected (%q, %q, line %d, column %d)",
+					i, tok.Type, lex.Text(tok), tok.Pos, want.typ, want.literal, want.line, want.column)
+			}
+		}
+	}
+}
+
+func TestNextToken_UnterminatedComment(t *testing.T) {
+	for _, keep := range []bool{false, true} {
+		opts := []Option{WithDialect(DialectJSONC)}
+		if keep {
+			opts = append(opts, KeepComments())
+		}
+		lex := NewLexer("[1] /* open\n", opts...)
+		lex.NextToken()
+		lex.NextToken()
+		lex.NextToken()
+
+		tok := lex.NextToken()
+		if tok.Type != TokenInvalid || tok.Err.Reason != ReasonUnterminatedComment || tok.Pos.Column != 5 {
+			t.Fatalf("Expected an unterminated comment at column 5, got (%q, %v)", tok.Type, tok.Err)
+		}
+		if msg := tok.Err.Error(); msg != "line 1, column 5: unterminated block comment: expected '*/' before the end of the input" {
+			t.Errorf("Unexpected message %q", msg)
+		}
+	}
+}
+
+// Under Strict, comments must be valid UTF-8 like the rest of the input.
+func TestNextToken_StrictComments(t *testing.T) {
+	for _, input := range []string{"{} // \xff", "{} /* \xff */", "{} /* \xc3 */"} {
+		for _, keep := range []bool{false, true} {
+			opts := []Option{WithDialect(DialectJSONC), Strict()}
+			if keep {
+				opts = append(opts, KeepComments())
+			}
+			lex := NewLexer(input, opts...)
+			lex.NextToken()
+			lex.NextToken()
+
+			tok := lex.NextToken()
+			if tok.Type != TokenInvalid || tok.Err.Reason != ReasonInvalidUTF8 || tok.Err.Pos.Offset != 6 {
+				t.Errorf("Input %q - expected invalid UTF-8 at offset 6, got (%q, %v)", input, tok.Type, tok.Err)
+			}
+		}
+	}
+
+	// Valid UTF-8 and, without Strict, any byte is fine.
+	for _, lex := range []*Lexer{
+		NewLexer("{} // café", WithDialect(DialectJSONC), Strict()),
+		NewLexer("{} /* \xff */", WithDialect(DialectJSONC)),
+	} {
+		for tok := lex.NextToken(); tok.Type != TokenEOF; tok = lex.NextToken() {
+			if tok.Type == TokenInvalid {
+				t.Errorf("Unexpected invalid token: %v", tok.Err)
+				break
+			}
+		}
+	}
+}
@@ -1,53 +1,378 @@
 package lexer
 
 import (
-	"strings"
-	"unicode"
+	"bytes"
+	"fmt"
+	"io"
+	"unicode/utf8"
 )
 
-// Token types
+// Position is a location in the lexer input.
+type Position struct {
+	Offset int // byte offset, starting at 0
+	Line   int // line number, starting at 1
+	Column int // column number in bytes, starting at 1
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("line %d, column %d", p.Line, p.Column)
+}
+
+// NumberKind tells integers from numbers with a fraction or exponent.
+type NumberKind int
+
 const (
-	TokenCurlyOpen   = "{"       // Represents `{`
-	TokenCurlyClose  = "}"       // Represents `}`
-	TokenSquareOpen  = "["       // Represents `[`
-	TokenSquareClose = "]"       // Represents `]`
-	TokenColon       = ":"       // Represents `:`
-	TokenComma       = ","       // Represents `,`
-	TokenString      = "STRING"  // Represents strings
-	TokenEOF         = "EOF"     // End of file/input
-	TokenInvalid     = "INVALID" // Invalid token
-	TokenNumber      = "NUMBER"  // Represents digit 0-9 including floats and exponents
-	TokenBool        = "BOOL"    // Represents Bool true/false
-	TokenNull        = "NULL"    // Represents Null
+	NumberNone    NumberKind = iota // Not a number token
+	NumberInteger                   // Digits only, e.g. -42
+	NumberFloat                     // Has a fraction or exponent, e.g. 4.2e1
 )
 
 type Token struct {
-	Type    string
+	Type    TokenType
 	Literal string
+	Number  NumberKind // kind of a TokenNumber
+	Err     *Error     // why a TokenInvalid was rejected
+	Pos     Position   // where the token starts
+	End     int        // byte offset just past the last byte of the token
 }
 
+// defaultBufSize is the initial buffer size of a reader-based Lexer.
+const defaultBufSize = 4096
+
+// bom is the UTF-8 encoding of U+FEFF.
+var bom = []byte{0xEF, 0xBB, 0xBF}
+
 // Lexer for tokenizing the input
 type Lexer struct {
-	input string
-	pos   int
+	r       io.Reader // nil when buf already holds the whole input
+	buf     []byte
+	pos     int   // read position in buf
+	start   int   // start of the current token in buf
+	base    int   // input offset of buf[0]
+	eof     bool  // r is exhausted
+	readErr error // read error other than io.EOF
+	err     error // first error: a read error or an invalid token
+
+	noCopy   bool      // tokens refer to buf instead of copying literals
+	strict   bool      // see Strict
+	bom      BOMPolicy // see WithBOM
+	dialect  Dialect   // see WithDialect
+	comments bool      // see KeepComments
+	started  bool      // a token has been read
+
+	// Tokens scanned ahead by Peek or kept for Reset. ring holds the
+	// tokens numbered from first, cur is the number of the next token
+	// NextToken returns and marks counts unreleased checkpoints.
+	ring  tokenRing
+	first int
+	cur   int
+	marks int
+
+	// Line bookkeeping: everything before the input offset counted has been
+	// scanned for newlines, line is the current line and lineStart the
+	// input offset of its first byte.
+	counted   int
+	line      int
+	lineStart int
 }
 
 // NewLexer creates a new Lexer
-func NewLexer(input string) *Lexer {
-	return &Lexer{input: strings.TrimSpace(input)}
+func NewLexer(input string, opts ...Option) *Lexer {
+	return newLexer(&Lexer{buf: []byte(input), eof: true}, opts)
+}
+
+// NewReaderLexer creates a Lexer that reads its input from r as needed.
+// Only the token being scanned is kept in memory, so the input may be
+// arbitrarily large as long as single tokens are not.
+func NewReaderLexer(r io.Reader, opts ...Option) *Lexer {
+	return newLexer(&Lexer{r: r, buf: make([]byte, 0, defaultBufSize)}, opts)
+}
+
+// NewBytesLexer creates a Lexer over data that does not allocate per
+// token: the Literal of STRING and NUMBER tokens is left empty and their
+// text is available from Bytes as a sub-slice of data, which must not be
+// modified while the lexer is in use.
+func NewBytesLexer(data []byte, opts ...Option) *Lexer {
+	return newLexer(&Lexer{buf: data, eof: true, noCopy: true}, opts)
+}
+
+// ResetBytes makes a lexer created by NewBytesLexer start over on data,
+// keeping its options and reusing its buffers.
+func (l *Lexer) ResetBytes(data []byte) {
+	*l = Lexer{
+		buf:      data,
+		eof:      true,
+		noCopy:   true,
+		strict:   l.strict,
+		bom:      l.bom,
+		dialect:  l.dialect,
+		comments: l.comments,
+		line:     1,
+		ring:     tokenRing{buf: l.ring.buf},
+	}
+}
+
+// ResetBytesAt is like ResetBytes for data found at pos in a larger input,
+// such as one line of newline-delimited JSON: tokens and errors get their
+// positions in that input. A byte order mark is only looked for at its
+// start.
+func (l *Lexer) ResetBytesAt(data []byte, pos Position) {
+	l.ResetBytes(data)
+	l.base, l.counted = pos.Offset, pos.Offset
+	l.line, l.lineStart = pos.Line, pos.Offset-pos.Column+1
+	l.started = pos.Offset > 0
 }
 
+func newLexer(l *Lexer, opts []Option) *Lexer {
+	l.line = 1
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Dialect returns the JSON syntax the lexer accepts.
+func (l *Lexer) Dialect() Dialect {
+	return l.dialect
+}
+
+// Err returns the first error the lexer ran into, either an invalid token
+// or a failure to read the input, as an *Error. It is nil if every token
+// scanned so far was valid.
+func (l *Lexer) Err() error {
+	return l.err
+}
+
+// NextToken returns the next token along with its position in the input.
 func (l *Lexer) NextToken() Token {
-	// Skip whitespace
-	for l.pos < len(l.input) && unicode.IsSpace(rune(l.input[l.pos])) {
+	tok := l.Peek(0)
+	l.cur++
+	l.discard()
+	return tok
+}
+
+// scanToken reads the next token from the input.
+func (l *Lexer) scanToken() Token {
+	l.start = l.pos
+	if !l.started {
+		l.started = true
+		if l.ensure(len(bom)) && bytes.HasPrefix(l.buf[l.pos:], bom) {
+			l.pos += len(bom)
+			if l.bom == BOMReject {
+				return l.finish(l.invalid(l.errorAt(ReasonBOM, l.start, l.pos, "byte order mark is not allowed")))
+			}
+			// Columns on the first line start after the mark.
+			l.counted, l.lineStart = len(bom), len(bom)
+			l.start = l.pos
+		}
+	}
+
+	if err := l.skipSpace(); err != nil {
+		return l.finish(l.invalid(err))
+	}
+	return l.finish(l.scan())
+}
+
+// Bytes returns the text of tok. For a lexer created by NewBytesLexer it
+// is the sub-slice of the input the token spans, without the quotes of a
+// STRING; otherwise it holds the bytes of tok.Literal.
+func (l *Lexer) Bytes(tok Token) []byte {
+	if !l.noCopy {
+		return []byte(tok.Literal)
+	}
+	start, end := tok.Pos.Offset-l.base, tok.End-l.base
+	if tok.Type == TokenString {
+		start, end = start+1, end-1
+	}
+	return l.buf[start:end]
+}
+
+// Text returns what the Literal of tok is for a lexer that copies
+// literals. It allocates for the STRING, NUMBER, IDENTIFIER, COMMENT and
+// INVALID tokens of a lexer created by NewBytesLexer.
+func (l *Lexer) Text(tok Token) string {
+	if !l.noCopy || tok.Literal != "" {
+		return tok.Literal
+	}
+	return string(l.Bytes(tok))
+}
+
+// literal returns the token text between buf[from:to], or "" if tokens
+// refer to buf.
+func (l *Lexer) literal(from, to int) string {
+	if l.noCopy {
+		return ""
+	}
+	return string(l.buf[from:to])
+}
+
+// finish sets the position of tok, which spans from l.start to l.pos.
+func (l *Lexer) finish(tok Token) Token {
+	tok.Pos = l.position(l.base + l.start)
+	tok.End = l.base + l.pos
+	if tok.Err != nil {
+		tok.Err.Pos = l.position(tok.Err.Pos.Offset)
+		if l.err == nil {
+			l.err = tok.Err
+		}
+	}
+	l.position(tok.End)
+	return tok
+}
+
+// errorAt returns an error about buf[from:to]. Its position is completed
+// by finish.
+func (l *Lexer) errorAt(reason Reason, from, to int, format string, args ...any) *Error {
+	return &Error{
+		Reason: reason,
+		Pos:    Position{Offset: l.base + from},
+		End:    l.base + to,
+		Msg:    fmt.Sprintf(format, args...),
+	}
+}
+
+// invalid returns an invalid token for the text scanned so far.
+func (l *Lexer) invalid(err *Error) Token {
+	return Token{Type: TokenInvalid, Literal: l.literal(l.start, l.pos), Err: err}
+}
+
+// more reports whether a byte is available at l.pos, reading more input
+// if necessary.
+func (l *Lexer) more() bool {
+	return l.pos < len(l.buf) || l.fill()
+}
+
+// ensure reads input until n bytes are available at l.pos and reports
+// whether it succeeded.
+func (l *Lexer) ensure(n int) bool {
+	for len(l.buf)-l.pos < n {
+		if !l.fill() {
+			return false
+		}
+	}
+	return true
+}
+
+// isSpace reports whether ch is whitespace between tokens. Bytes of
+// multi-byte UTF-8 characters never are. Strict limits JSON and JSONC to
+// the four JSON whitespace characters; JSON5 defines vertical tab and form
+// feed as whitespace too.
+func (l *Lexer) isSpace(ch byte) bool {
+	switch ch {
+	case ' ', '\t', '\n', '\r':
+		return true
+	case '\v', '\f':
+		return !l.strict || l.dialect == DialectJSON5
+	}
+	return false
+}
+
+// skipSpace skips whitespace between tokens and, unless they are kept,
+// the comments of JSON5 and JSONC. It returns an error for a block comment
+// that is not closed.
+func (l *Lexer) skipSpace() *Error {
+	for l.more() {
+		ch := l.buf[l.pos]
+		switch {
+		case l.isSpace(ch):
+			l.pos++
+		case ch == '/' && l.dialect != DialectJSON:
+			if l.comments {
+				return nil
+			}
+			skipped, err := l.skipComment()
+			if err != nil || !skipped {
+				return err
+			}
+		case ch >= utf8.RuneSelf && l.dialect == DialectJSON5:
+			if !l.skipUnicodeSpace() {
+				return nil
+			}
+		default:
+			return nil
+		}
+		l.start = l.pos
+	}
+	return nil
+}
+
+// scanRune advances past the UTF-8 encoded character at l.pos, or returns
+// an error if it is not valid UTF-8.
+func (l *Lexer) scanRune() (rune, *Error) {
+	for !utf8.FullRune(l.buf[l.pos:]) && l.fill() {
+	}
+	r, size := utf8.DecodeRune(l.buf[l.pos:])
+	if r == utf8.RuneError && size <= 1 {
 		l.pos++
+		return r, l.errorAt(ReasonInvalidUTF8, l.pos-1, l.pos, "invalid UTF-8 byte 0x%02X", l.buf[l.pos-1])
+	}
+	l.pos += size
+	return r, nil
+}
+
+// fill reads more input into buf, discarding everything before the current
+// token and growing buf when the token fills it. It reports whether any
+// bytes were added.
+func (l *Lexer) fill() bool {
+	if l.eof {
+		return false
 	}
 
-	if l.pos >= len(l.input) {
+	if l.start > 0 {
+		l.position(l.base + l.start)
+		n := copy(l.buf, l.buf[l.start:])
+		l.buf = l.buf[:n]
+		l.base += l.start
+		l.pos -= l.start
+		l.start = 0
+	}
+	if len(l.buf) == cap(l.buf) {
+		buf := make([]byte, len(l.buf), 2*cap(l.buf))
+		copy(buf, l.buf)
+		l.buf = buf
+	}
+
+	for {
+		n, err := l.r.Read(l.buf[len(l.buf):cap(l.buf)])
+		l.buf = l.buf[:len(l.buf)+n]
+		if err != nil {
+			if err != io.EOF {
+				l.readErr = err
+			}
+			l.eof = true
+			return n > 0
+		}
+		if n > 0 {
+			return true
+		}
+	}
+}
+
+// position returns the Position of the input offset, counting the newlines
+// between the last counted offset and offset. Offsets must not go backwards
+// or before the current token.
+func (l *Lexer) position(offset int) Position {
+	for ; l.counted < offset; l.counted++ {
+		if l.buf[l.counted-l.base] == '\n' {
+			l.line++
+			l.lineStart = l.counted + 1
+		}
+	}
+	return Position{Offset: offset, Line: l.line, Column: offset - l.lineStart + 1}
+}
+
+// scan reads the token starting at l.pos.
+func (l *Lexer) scan() Token {
+	if !l.more() {
+		if l.readErr != nil {
+			err := l.errorAt(ReasonIO, l.pos, l.pos, "error reading input: %v", l.readErr)
+			err.Err = l.readErr
+			return l.invalid(err)
+		}
 		return Token{Type: TokenEOF}
 	}
 
-	ch := l.input[l.pos]
+	ch := l.buf[l.pos]
 
 	switch ch {
 	case '{':
@@ -69,100 +394,212 @@ func (l *Lexer) NextToken() Token {
 		l.pos++
 		return Token{Type: TokenComma, Literal: ","}
 	case '"':
-		l.pos++
-		start := l.pos
-		escaped := false
-		for l.pos < len(l.input) {
-			ch := l.input[l.pos]
-			if escaped {
-				escaped = false
-				l.pos++
-				continue
-			}
-			if ch == '\\' {
-				escaped = true
-				l.pos++
-				continue
-			}
-			if ch == '"' {
-				break
+		return l.scanString('"')
+	case '\'':
+		if l.dialect == DialectJSON5 {
+			return l.scanString('\'')
+		}
+	case '/':
+		if l.dialect != DialectJSON {
+			if tok, ok := l.scanComment(); ok {
+				return tok
 			}
+		}
+	}
+
+	if l.dialect == DialectJSON5 {
+		if l.identStart() {
+			return l.scanIdentifier()
+		}
+		if isDigit(ch) || ch == '-' || ch == '+' || ch == '.' {
+			return l.scanNumber5()
+		}
+	}
+
+	if isAlpha(ch) {
+		for l.more() && isAlpha(l.buf[l.pos]) {
 			l.pos++
 		}
-		if l.pos >= len(l.input) {
-			return Token{Type: TokenInvalid, Literal: "Unterminated string"}
+		switch string(l.buf[l.start:l.pos]) {
+		case "true":
+			return Token{Type: TokenBool, Literal: "true"}
+		case "false":
+			return Token{Type: TokenBool, Literal: "false"}
+		case "null":
+			return Token{Type: TokenNull, Literal: "null"}
+		default:
+			return l.invalid(l.errorAt(ReasonUnknownLiteral, l.start, l.pos, "unknown literal '%s'", l.buf[l.start:l.pos]))
 		}
-		literal := l.input[start:l.pos]
-		l.pos++ // skip closing quote
-		return Token{Type: TokenString, Literal: literal}
+	} else if isDigit(ch) || ch == '-' {
+		return l.scanNumber()
+	}
 
-	default:
-		if isAlpha(ch) {
-			start := l.pos
-			for l.pos < len(l.input) && isAlpha(l.input[l.pos]) {
-				l.pos++
+	// Unknown/invalid character
+	if ch >= utf8.RuneSelf {
+		if l.strict {
+			r, err := l.scanRune()
+			if err == nil {
+				err = l.errorAt(ReasonUnexpectedChar, l.start, l.pos, "unexpected character %U", r)
 			}
-			word := l.input[start:l.pos]
-			switch word {
-			case "true", "false":
-				return Token{Type: TokenBool, Literal: word}
-			case "null":
-				return Token{Type: TokenNull, Literal: word}
-			default:
-				return Token{Type: TokenInvalid, Literal: word}
-			}
-		} else if isDigit(ch) || ch == '-' {
-			start := l.pos
+			return l.invalid(err)
+		}
+		for !utf8.FullRune(l.buf[l.pos:]) && l.fill() {
+		}
+		r, size := utf8.DecodeRune(l.buf[l.pos:])
+		l.pos += size
+		if r == utf8.RuneError && size == 1 {
+			return l.invalid(l.errorAt(ReasonUnexpectedChar, l.start, l.pos, "unexpected byte 0x%02X", ch))
+		}
+		return l.invalid(l.errorAt(ReasonUnexpectedChar, l.start, l.pos, "unexpected character %U", r))
+	}
+	l.pos++
+	return l.invalid(l.errorAt(ReasonUnexpectedChar, l.start, l.pos, "unexpected character %q", ch))
+}
 
-			// minus
-			if l.input[l.pos] == '-' {
-				l.pos++
+// scanString reads a string token ending in quote, validating escape
+// sequences and rejecting unescaped control characters as RFC 8259
+// requires. JSON5 only rejects line breaks.
+func (l *Lexer) scanString(quote byte) Token {
+	l.pos++ // skip opening quote
+	for l.more() {
+		ch := l.buf[l.pos]
+		switch {
+		case ch == quote:
+			literal := l.literal(l.start+1, l.pos)
+			l.pos++ // skip closing quote
+			return Token{Type: TokenString, Literal: literal}
+		case ch == '\\':
+			if err := l.scanEscape(); err != nil {
+				return l.invalid(err)
 			}
-
-			// Integer part
-			for l.pos < len(l.input) && isDigit(l.input[l.pos]) {
-				l.pos++
+		case ch < 0x20 && (l.dialect != DialectJSON5 || ch == '\n' || ch == '\r'):
+			l.pos++
+			return l.invalid(l.errorAt(ReasonControlChar, l.pos-1, l.pos, "invalid control character %U in string", ch))
+		case ch >= utf8.RuneSelf && l.strict:
+			if _, err := l.scanRune(); err != nil {
+				return l.invalid(err)
 			}
+		default:
+			l.pos++
+		}
+	}
+	return l.invalid(l.errorAt(ReasonUnterminatedString, l.start, l.pos, "unterminated string"))
+}
 
-			// Fractional part
-			if l.pos < len(l.input) && l.input[l.pos] == '.' {
-				l.pos++
-				for l.pos < len(l.input) && isDigit(l.input[l.pos]) {
-					l.pos++
-				}
-			}
+// scanEscape reads the escape sequence at l.pos, or returns an error if it
+// is not valid.
+func (l *Lexer) scanEscape() *Error {
+	from := l.base + l.pos // absolute, as filling may move buf
+	l.pos++                // skip backslash
+	if !l.more() {
+		return l.errorAt(ReasonUnterminatedString, l.start, l.pos, "unterminated string")
+	}
 
-			// Exponent part
-			if l.pos < len(l.input) && (l.input[l.pos] == 'e' || l.input[l.pos] == 'E') {
-				l.pos++ // skip 'e' or 'E'
-
-				if l.pos < len(l.input) && (l.input[l.pos] == '+' || l.input[l.pos] == '-') {
-					l.pos++ // optional '+' or '-'
-				}
-
-				// Require at least one digit after e/E
-				if l.pos >= len(l.input) || !isDigit(l.input[l.pos]) {
-					return Token{Type: TokenInvalid, Literal: l.input[start:l.pos]}
-				}
-				for l.pos < len(l.input) && isDigit(l.input[l.pos]) {
-					l.pos++
-				}
-			}
+	ch := l.buf[l.pos]
+	if l.dialect == DialectJSON5 {
+		return l.scanEscape5(from)
+	}
+	l.pos++
+	switch ch {
+	case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+		return nil
+	case 'u':
+		return l.skipHex(from, 4, "four")
+	}
+	if ch < 0x20 {
+		return l.errorAt(ReasonBadEscape, from-l.base, l.pos, "invalid escape sequence: backslash followed by control character %U in string", ch)
+	}
+	return l.errorAt(ReasonBadEscape, from-l.base, l.pos, "invalid escape sequence '\\%c' in string", ch)
+}
 
-			return Token{Type: TokenNumber, Literal: l.input[start:l.pos]}
+// skipHex reads the n hex digits of the escape sequence that starts at the
+// input offset from.
+func (l *Lexer) skipHex(from, n int, count string) *Error {
+	for i := 0; i < n; i++ {
+		if !l.more() || !isHexDigit(l.buf[l.pos]) {
+			return l.errorAt(ReasonBadEscape, from-l.base, l.pos, "invalid escape sequence '%s' in string: expected %s hex digits", l.buf[from-l.base:l.pos], count)
 		}
+		l.pos++
+	}
+	return nil
+}
+
+// scanNumber reads a number token following the RFC 8259 grammar: an
+// optional minus, an integer part that is either 0 or starts with 1-9, an
+// optional fraction and an optional exponent, each with at least one digit.
+func (l *Lexer) scanNumber() Token {
+	kind := NumberInteger
 
-		// Unknown/invalid character
+	// minus
+	if l.buf[l.pos] == '-' {
+		l.pos++
+	}
+
+	// Integer part
+	switch {
+	case l.more() && l.buf[l.pos] == '.':
+		return l.invalidNumber("expected digit before decimal point")
+	case !l.more() || !isDigit(l.buf[l.pos]):
+		return l.invalidNumber("expected digit after '-'")
+	case l.buf[l.pos] == '0':
+		l.pos++
+		if l.more() && isDigit(l.buf[l.pos]) {
+			l.skipDigits()
+			return l.invalidNumber("leading zeros are not allowed")
+		}
+	default:
+		l.skipDigits()
+	}
+
+	// Fractional part
+	if l.more() && l.buf[l.pos] == '.' {
+		kind = NumberFloat
+		l.pos++
+		if !l.more() || !isDigit(l.buf[l.pos]) {
+			return l.invalidNumber("expected digit after decimal point")
+		}
+		l.skipDigits()
+	}
+
+	// Exponent part
+	if l.more() && (l.buf[l.pos] == 'e' || l.buf[l.pos] == 'E') {
+		kind = NumberFloat
+		l.pos++ // skip 'e' or 'E'
+
+		if l.more() && (l.buf[l.pos] == '+' || l.buf[l.pos] == '-') {
+			l.pos++ // optional '+' or '-'
+		}
+
+		// Require at least one digit after e/E
+		if !l.more() || !isDigit(l.buf[l.pos]) {
+			return l.invalidNumber("expected digit in exponent")
+		}
+		l.skipDigits()
+	}
+
+	return Token{Type: TokenNumber, Literal: l.literal(l.start, l.pos), Number: kind}
+}
+
+func (l *Lexer) skipDigits() {
+	for l.more() && isDigit(l.buf[l.pos]) {
 		l.pos++
-		return Token{Type: TokenInvalid, Literal: string(ch)}
 	}
 }
 
+// invalidNumber returns an invalid token for the number scanned so far.
+func (l *Lexer) invalidNumber(reason string) Token {
+	return l.invalid(l.errorAt(ReasonBadNumber, l.start, l.pos, "invalid number '%s': %s", l.buf[l.start:l.pos], reason))
+}
+
 // isAlpha returns true if ch is a letter (A-Z or a-z)
 func isAlpha(ch byte) bool {
-	return unicode.IsLetter(rune(ch))
+	return (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')
 }
 
 func isDigit(ch byte) bool {
 	return ch >= '0' && ch <= '9'
 }
+
+func isHexDigit(ch byte) bool {
+	return isDigit(ch) || (ch >= 'a' && ch <= 'f') || (ch >= 'A' && ch <= 'F')
+}
@@ -1,7 +1,11 @@
 package lexer
 
 import (
+	"errors"
+	"io"
+	"strings"
 	"testing"
+	"testing/iotest"
 )
 
 func TestNextToken_EmptyObject(t *testing.T) {
@@ -129,3 +133,508 @@ func TestNextToken_Array(t *testing.T) {
 		}
 	}
 }
+
+func TestNextToken_Positions(t *testing.T) {
+	input := "  {\n  \"key\": [1,\n\t-2.5e3]\n}"
+	expected := []struct {
+		Type   TokenType
+		Offset int
+		Line   int
+		Column int
+		End    int
+	}{
+		{TokenCurlyOpen, 2, 1, 3, 3},
+		{TokenString, 6, 2, 3, 11},
+		{TokenColon, 11, 2, 8, 12},
+		{TokenSquareOpen, 13, 2, 10, 14},
+		{TokenNumber, 14, 2, 11, 15},
+		{TokenComma, 15, 2, 12, 16},
+		{TokenNumber, 18, 3, 2, 24},
+		{TokenSquareClose, 24, 3, 8, 25},
+		{TokenCurlyClose, 26, 4, 1, 27},
+		{TokenEOF, 27, 4, 2, 27},
+	}
+
+	lex := NewLexer(input)
+	for i, exp := range expected {
+		tok := lex.NextToken()
+		if tok.Type != exp.Type {
+			t.Fatalf("Token %d - got type %q, expected %q", i, tok.Type, exp.Type)
+		}
+		if tok.Pos.Offset != exp.Offset || tok.Pos.Line != exp.Line || tok.Pos.Column != exp.Column || tok.End != exp.End {
+			t.Errorf("Token %d (%q) - got offset %d, line %d, column %d, end %d; expected offset %d, line %d, column %d, end %d",
+				i, tok.Type, tok.Pos.Offset, tok.Pos.Line, tok.Pos.Column, tok.End, exp.Offset, exp.Line, exp.Column, exp.End)
+		}
+	}
+}
+
+func TestNewReaderLexer_MatchesStringLexer(t *testing.T) {
+	input := "{\n  \"key\": \"a \\\"quoted\\\" value\",\n  \"nums\": [1, -2.5, 3e10],\n  \"ok\": true,\n  \"none\": null\n}\n"
+
+	want := NewLexer(input)
+	// OneByteReader forces a refill for every byte, so tokens span reads.
+	got := NewReaderLexer(iotest.OneByteReader(strings.NewReader(input)))
+	for i := 0; ; i++ {
+		exp := want.NextToken()
+		tok := got.NextToken()
+		if tok != exp {
+			t.Fatalf("Token %d - got %+v, expected %+v", i, tok, exp)
+		}
+		if exp.Type == TokenEOF {
+			break
+		}
+	}
+}
+
+func TestNewReaderLexer_LongLine(t *testing.T) {
+	// Longer than bufio.Scanner's default 64 KiB token limit.
+	value := strings.Repeat("x", 100*1024)
+	lex := NewReaderLexer(strings.NewReader(`["` + value + `"]`))
+
+	_ = lex.NextToken() // [
+	tok := lex.NextToken()
+	if tok.Type != TokenString || tok.Literal != value {
+		t.Fatalf("Expected %d byte STRING token, got %q of %d bytes", len(value), tok.Type, len(tok.Literal))
+	}
+	if tok.End != len(value)+3 {
+		t.Errorf("Expected string to end at offset %d, got %d", len(value)+3, tok.End)
+	}
+}
+
+func TestNewReaderLexer_ReadError(t *testing.T) {
+	lex := NewReaderLexer(iotest.ErrReader(io.ErrUnexpectedEOF))
+
+	tok := lex.NextToken()
+	if tok.Type != TokenInvalid {
+		t.Errorf("Expected INVALID token, got %q", tok.Type)
+	}
+	if tok.Err == nil || tok.Err.Reason != ReasonIO {
+		t.Errorf("Expected a read error on the token, got %v", tok.Err)
+	}
+	if !errors.Is(lex.Err(), io.ErrUnexpectedEOF) {
+		t.Errorf("Expected Err() to wrap %v, got %v", io.ErrUnexpectedEOF, lex.Err())
+	}
+}
+
+func TestNextToken_ValidEscapes(t *testing.T) {
+	input := `"\" \\ \/ \b \f \n \r \t \u00e9 \ud83d\ude00"`
+	lex := NewLexer(input)
+
+	tok := lex.NextToken()
+	if tok.Type != TokenString || tok.Literal != input[1:len(input)-1] {
+		t.Errorf("Expected STRING token %q, got (%q, %q)", input[1:len(input)-1], tok.Type, tok.Literal)
+	}
+}
+
+func TestNextToken_InvalidStrings(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		reason  Reason
+		offset  int
+		end     int
+		message string
+	}{
+		{"UnknownEscape", `"\x15"`, ReasonBadEscape, 1, 3, `invalid escape sequence '\x' in string`},
+		{"OctalEscape", `"\017"`, ReasonBadEscape, 1, 3, `invalid escape sequence '\0' in string`},
+		{"ShortUnicodeEscape", `"\u12"`, ReasonBadEscape, 1, 5, `invalid escape sequence '\u12' in string: expected four hex digits`},
+		{"NonHexUnicodeEscape", `"\u12G4"`, ReasonBadEscape, 1, 5, `invalid escape sequence '\u12' in string: expected four hex digits`},
+		{"RawTab", "\"tab\there\"", ReasonControlChar, 4, 5, "invalid control character U+0009 in string"},
+		{"RawNewline", "\"line\nbreak\"", ReasonControlChar, 5, 6, "invalid control character U+000A in string"},
+		{"EscapedNewline", "\"line\\\nbreak\"", ReasonBadEscape, 5, 7, "invalid escape sequence: backslash followed by control character U+000A in string"},
+		{"Unterminated", `"abc`, ReasonUnterminatedString, 0, 4, "unterminated string"},
+		{"UnterminatedEscape", `"abc\`, ReasonUnterminatedString, 0, 5, "unterminated string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tok := NewLexer(tt.input).NextToken()
+			if tok.Type != TokenInvalid || tok.Err == nil {
+				t.Fatalf("Expected INVALID token with an error, got %+v", tok)
+			}
+			if tok.Err.Reason != tt.reason || tok.Err.Msg != tt.message {
+				t.Errorf("got (%s, %q), expected (%s, %q)", tok.Err.Reason, tok.Err.Msg, tt.reason, tt.message)
+			}
+			if tok.Err.Pos.Offset != tt.offset || tok.Err.End != tt.end {
+				t.Errorf("got error span %d-%d, expected %d-%d", tok.Err.Pos.Offset, tok.Err.End, tt.offset, tt.end)
+			}
+		})
+	}
+}
+
+func TestNextToken_Numbers(t *testing.T) {
+	tests := []struct {
+		input string
+		kind  NumberKind
+	}{
+		{"0", NumberInteger},
+		{"-0", NumberInteger},
+		{"42", NumberInteger},
+		{"-9007199254740993", NumberInteger},
+		{"0.5", NumberFloat},
+		{"-12.75", NumberFloat},
+		{"1e10", NumberFloat},
+		{"1E+2", NumberFloat},
+		{"2.5e-3", NumberFloat},
+		{"0e0", NumberFloat},
+	}
+
+	for _, tt := range tests {
+		tok := NewLexer(tt.input).NextToken()
+		if tok.Type != TokenNumber || tok.Literal != tt.input || tok.Number != tt.kind {
+			t.Errorf("%s - got (%q, %q, %d), expected (%q, %q, %d)", tt.input, tok.Type, tok.Literal, tok.Number, TokenNumber, tt.input, tt.kind)
+		}
+	}
+}
+
+func TestNextToken_InvalidNumbers(t *testing.T) {
+	tests := []struct {
+		input   string
+		literal string
+		message string
+	}{
+		{"-", "-", "invalid number '-': expected digit after '-'"},
+		{"-a", "-", "invalid number '-': expected digit after '-'"},
+		{"-.5", "-", "invalid number '-': expected digit before decimal point"},
+		{"0123", "0123", "invalid number '0123': leading zeros are not allowed"},
+		{"-012", "-012", "invalid number '-012': leading zeros are not allowed"},
+		{"1.", "1.", "invalid number '1.': expected digit after decimal point"},
+		{"1.e5", "1.", "invalid number '1.': expected digit after decimal point"},
+		{"1e", "1e", "invalid number '1e': expected digit in exponent"},
+		{"1e+", "1e+", "invalid number '1e+': expected digit in exponent"},
+	}
+
+	for _, tt := range tests {
+		tok := NewLexer(tt.input).NextToken()
+		if tok.Type != TokenInvalid || tok.Literal != tt.literal || tok.Err == nil {
+			t.Fatalf("%s - got %+v, expected INVALID token %q with an error", tt.input, tok, tt.literal)
+		}
+		if tok.Err.Reason != ReasonBadNumber || tok.Err.Msg != tt.message {
+			t.Errorf("%s - got (%s, %q), expected (%s, %q)", tt.input, tok.Err.Reason, tok.Err.Msg, ReasonBadNumber, tt.message)
+		}
+	}
+}
+
+func TestNextToken_StrictUTF8(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		reason  Reason
+		offset  int
+		message string
+	}{
+		{"InvalidByteInString", "[\"ok\xffok\"]", ReasonInvalidUTF8, 4, "invalid UTF-8 byte 0xFF"},
+		{"TruncatedSequence", "[\"caf\xc3\"]", ReasonInvalidUTF8, 5, "invalid UTF-8 byte 0xC3"},
+		{"Overlong", "[\"\xc0\xafx\"]", ReasonInvalidUTF8, 2, "invalid UTF-8 byte 0xC0"},
+		{"InvalidByteOutsideString", "[\xff]", ReasonInvalidUTF8, 1, "invalid UTF-8 byte 0xFF"},
+		{"NoBreakSpace", "[\xc2\xa01]", ReasonUnexpectedChar, 1, "unexpected character U+00A0"},
+		{"VerticalTab", "[\v1]", ReasonUnexpectedChar, 1, `unexpected character '\v'`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lex := NewLexer(tt.input, Strict())
+			_ = lex.NextToken() // [
+			tok := lex.NextToken()
+			if tok.Type != TokenInvalid || tok.Err == nil {
+				t.Fatalf("Expected INVALID token with an error, got %+v", tok)
+			}
+			if tok.Err.Reason != tt.reason || tok.Err.Pos.Offset != tt.offset || tok.Err.Msg != tt.message {
+				t.Errorf("got (%s, %d, %q), expected (%s, %d, %q)", tok.Err.Reason, tok.Err.Pos.Offset, tok.Err.Msg, tt.reason, tt.offset, tt.message)
+			}
+		})
+	}
+
+	tok := NewLexer("\"héllo 世界\"", Strict()).NextToken()
+	if tok.Type != TokenString {
+		t.Errorf("Expected valid UTF-8 to lex as STRING, got (%q, %q)", tok.Type, tok.Literal)
+	}
+}
+
+// Without Strict, whitespace is still ASCII: the bytes of a multi-byte
+// character are never skipped as whitespace.
+func TestNextToken_Whitespace(t *testing.T) {
+	for _, input := range []string{"[1\xa0]", "[\x85true]", "[\xc2\xa01]"} {
+		lex := NewLexer(input)
+		for tok := lex.NextToken(); tok.Type != TokenEOF; tok = lex.NextToken() {
+			if tok.Type == TokenInvalid {
+				break
+			}
+		}
+		if lex.Err() == nil {
+			t.Errorf("Expected %q to have an invalid token", input)
+		}
+	}
+
+	lex := NewLexer("[\v1\f]")
+	for _, typ := range []TokenType{TokenSquareOpen, TokenNumber, TokenSquareClose, TokenEOF} {
+		if tok := lex.NextToken(); tok.Type != typ {
+			t.Errorf("Got %q, expected %q", tok.Type, typ)
+		}
+	}
+}
+
+// Without Strict, a non-ASCII character is named by its code point, and
+// a byte that is not valid UTF-8 by its value.
+func TestNextToken_UnexpectedNonASCII(t *testing.T) {
+	tests := []struct {
+		input   string
+		message string
+		end     int
+	}{
+		{"[é]", "unexpected character U+00E9", 3},
+		{"[世]", "unexpected character U+4E16", 4},
+		{"[\xff]", "unexpected byte 0xFF", 2},
+		{"[\xc3]", "unexpected byte 0xC3", 2},
+	}
+
+	for _, tt := range tests {
+		for _, lex := range []*Lexer{NewLexer(tt.input), NewReaderLexer(iotest.OneByteReader(strings.NewReader(tt.input)))} {
+			lex.NextToken()
+			tok := lex.NextToken()
+			if tok.Type != TokenInvalid || tok.Err.Msg != tt.message || tok.End != tt.end {
+				t.Errorf("Input %q - got (%q, %v, end %d), expected (%q, end %d)", tt.input, tok.Type, tok.Err, tok.End, tt.message, tt.end)
+			}
+		}
+	}
+}
+
+func TestNextToken_ByteOrderMark(t *testing.T) {
+	input := "\xef\xbb\xbf{}"
+
+	tok := NewLexer(input).NextToken()
+	if tok.Type != TokenInvalid || tok.Pos.Offset != 0 || tok.End != 3 {
+		t.Errorf("Expected INVALID token spanning the mark, got %+v", tok)
+	}
+
+	lex := NewLexer(input, WithBOM(BOMStrip))
+	tok = lex.NextToken()
+	if tok.Type != TokenCurlyOpen || tok.Pos.Offset != 3 || tok.Pos.Column != 1 {
+		t.Errorf("Expected '{' at offset 3, column 1, got %+v", tok)
+	}
+
+	lex = NewReaderLexer(strings.NewReader(input), WithBOM(BOMStrip))
+	if tok = lex.NextToken(); tok.Type != TokenCurlyOpen {
+		t.Errorf("Expected '{' from reader lexer, got %+v", tok)
+	}
+}
+
+func TestTokenType_Categories(t *testing.T) {
+	tests := []struct {
+		typ        TokenType
+		name       string
+		value      bool
+		structural bool
+		isError    bool
+		kind       Kind
+	}{
+		{TokenCurlyOpen, "{", false, true, false, KindNone},
+		{TokenComma, ",", false, true, false, KindNone},
+		{TokenString, "STRING", true, false, false, KindString},
+		{TokenNumber, "NUMBER", true, false, false, KindNumber},
+		{TokenBool, "BOOL", true, false, false, KindBool},
+		{TokenNull, "NULL", true, false, false, KindNull},
+		{TokenEOF, "EOF", false, false, false, KindNone},
+		{TokenInvalid, "INVALID", false, false, true, KindNone},
+	}
+
+	for _, tt := range tests {
+		if tt.typ.String() != tt.name {
+			t.Errorf("String() = %q, expected %q", tt.typ.String(), tt.name)
+		}
+		if tt.typ.IsValue() != tt.value || tt.typ.IsStructural() != tt.structural || tt.typ.IsError() != tt.isError {
+			t.Errorf("%s - got IsValue %v, IsStructural %v, IsError %v", tt.name, tt.typ.IsValue(), tt.typ.IsStructural(), tt.typ.IsError())
+		}
+		if tt.typ.Kind() != tt.kind {
+			t.Errorf("%s - got Kind %s, expected %s", tt.name, tt.typ.Kind(), tt.kind)
+		}
+	}
+}
+
+func TestLexer_Peek(t *testing.T) {
+	lex := NewLexer(`[1, true]`)
+
+	if tok := lex.Peek(2); tok.Type != TokenComma {
+		t.Errorf("Peek(2) - got %q, expected %q", tok.Type, TokenComma)
+	}
+	if tok := lex.Peek(0); tok.Type != TokenSquareOpen {
+		t.Errorf("Peek(0) - got %q, expected %q", tok.Type, TokenSquareOpen)
+	}
+
+	expected := []TokenType{TokenSquareOpen, TokenNumber, TokenComma, TokenBool, TokenSquareClose, TokenEOF}
+	for i, typ := range expected {
+		if tok := lex.NextToken(); tok.Type != typ {
+			t.Errorf("Token %d - got %q, expected %q", i, tok.Type, typ)
+		}
+	}
+	if tok := lex.Peek(3); tok.Type != TokenEOF {
+		t.Errorf("Peek past the end - got %q, expected %q", tok.Type, TokenEOF)
+	}
+}
+
+func TestLexer_MarkReset(t *testing.T) {
+	input := `{"a": [1, 2], "b": null}`
+	for name, lex := range map[string]*Lexer{
+		"String": NewLexer(input),
+		"Reader": NewReaderLexer(iotest.OneByteReader(strings.NewReader(input))),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_ = lex.NextToken() // {
+
+			outer := lex.Mark()
+			key := lex.NextToken()
+			_ = lex.NextToken() // :
+
+			inner := lex.Mark()
+			for i := 0; i < 4; i++ {
+				_ = lex.NextToken() // [ 1 , 2
+			}
+			lex.Reset(inner)
+			if tok := lex.NextToken(); tok.Type != TokenSquareOpen {
+				t.Errorf("After Reset(inner) - got %q, expected %q", tok.Type, TokenSquareOpen)
+			}
+			lex.Release(inner)
+
+			lex.Reset(outer)
+			if tok := lex.NextToken(); tok != key {
+				t.Errorf("After Reset(outer) - got %+v, expected %+v", tok, key)
+			}
+			lex.Release(outer)
+
+			expected := []TokenType{TokenColon, TokenSquareOpen, TokenNumber, TokenComma, TokenNumber, TokenSquareClose}
+			for i, typ := range expected {
+				if tok := lex.NextToken(); tok.Type != typ {
+					t.Errorf("Token %d - got %q, expected %q", i, tok.Type, typ)
+				}
+			}
+			if lex.ring.len() != 0 {
+				t.Errorf("Expected released tokens to be discarded, %d still buffered", lex.ring.len())
+			}
+		})
+	}
+}
+
+func TestNewBytesLexer_ReferencesInput(t *testing.T) {
+	input := []byte(`{"name": "Alice", "age": 30, "tags": ["a\"b"], "ok": true}`)
+	want := NewLexer(string(input))
+	lex := NewBytesLexer(input)
+
+	for i := 0; ; i++ {
+		exp := want.NextToken()
+		tok := lex.NextToken()
+		if tok.Type != exp.Type || tok.Pos != exp.Pos || tok.End != exp.End {
+			t.Fatalf("Token %d - got %+v, expected %+v", i, tok, exp)
+		}
+		if (tok.Type == TokenString || tok.Type == TokenNumber) && tok.Literal != "" {
+			t.Errorf("Token %d - expected empty Literal, got %q", i, tok.Literal)
+		}
+		if got := string(lex.Bytes(tok)); got != exp.Literal {
+			t.Errorf("Token %d - Bytes() = %q, expected %q", i, got, exp.Literal)
+		}
+		if got := lex.Text(tok); got != exp.Literal {
+			t.Errorf("Token %d - Text() = %q, expected %q", i, got, exp.Literal)
+		}
+		if exp.Type == TokenEOF {
+			break
+		}
+	}
+}
+
+// A lexer reset at a position in a larger input reports positions in it.
+func TestResetBytesAt(t *testing.T) {
+	input := "[1]\n{\"a\": \"x\" x}"
+	want := NewLexer(input)
+	for range 3 {
+		want.NextToken()
+	}
+
+	lex := NewBytesLexer(nil)
+	lex.ResetBytesAt([]byte(input[4:]), Position{Offset: 4, Line: 2, Column: 1})
+	for i := 0; ; i++ {
+		exp := want.NextToken()
+		tok := lex.NextToken()
+		if tok.Type != exp.Type || tok.Pos != exp.Pos || tok.End != exp.End || lex.Text(tok) != exp.Literal {
+			t.Fatalf("Token %d - got %+v, expected %+v", i, tok, exp)
+		}
+		if exp.Type == TokenInvalid && tok.Err.Pos != exp.Err.Pos {
+			t.Errorf("Token %d - got error at %s, expected %s", i, tok.Err.Pos, exp.Err.Pos)
+		}
+		if exp.Type == TokenEOF {
+			break
+		}
+	}
+}
+
+var benchmarkDocuments = map[string]string{
+	"Object": `{
+  "name": "Alice",
+  "age": 30,
+  "isStudent": false,
+  "scores": [98, 87.5, 92e1],
+  "profile": {
+    "email": "alice@example.com",
+    "phones": ["123-456", "789-012"],
+    "bio": "Likes \"quotes\", tabs\tand cafés"
+  },
+  "spouse": null
+}`,
+	"Numbers": "[" + strings.Repeat("-12.5e3, 0, 42, 3.14159, ", 100) + "1]",
+	"Strings": "[" + strings.Repeat(`"the quick brown fox", "jumps\nover", `, 100) + `""]`,
+}
+
+func BenchmarkBytesLexer(b *testing.B) {
+	for name, doc := range benchmarkDocuments {
+		b.Run(name, func(b *testing.B) {
+			data := []byte(doc)
+			lex := NewBytesLexer(data, Strict())
+			b.SetBytes(int64(len(data)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				lex.ResetBytes(data)
+				for tok := lex.NextToken(); tok.Type != TokenEOF; tok = lex.NextToken() {
+					if tok.Type == TokenInvalid {
+						b.Fatalf("Unexpected invalid token: %s", tok.Literal)
+					}
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkLexer(b *testing.B) {
+	for name, doc := range benchmarkDocuments {
+		b.Run(name, func(b *testing.B) {
+			b.SetBytes(int64(len(doc)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				lex := NewLexer(doc)
+				for tok := lex.NextToken(); tok.Type != TokenEOF; tok = lex.NextToken() {
+					if tok.Type == TokenInvalid {
+						b.Fatalf("Unexpected invalid token: %s", tok.Literal)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestLexer_Err(t *testing.T) {
+	lex := NewLexer(`{"a": tru, "b": 01}`)
+	for tok := lex.NextToken(); tok.Type != TokenEOF; tok = lex.NextToken() {
+	}
+
+	var err *Error
+	if !errors.As(lex.Err(), &err) {
+		t.Fatalf("Expected Err() to return an *Error, got %v", lex.Err())
+	}
+	if err.Reason != ReasonUnknownLiteral || err.Pos.Offset != 6 || err.End != 9 {
+		t.Errorf("Expected the first error, got %s at %d-%d", err.Reason, err.Pos.Offset, err.End)
+	}
+	if msg := err.Error(); msg != "line 1, column 7: unknown literal 'tru'" {
+		t.Errorf("Unexpected message %q", msg)
+	}
+
+	if err := NewLexer(`[1]`).Err(); err != nil {
+		t.Errorf("Expected no error before reading, got %v", err)
+	}
+}
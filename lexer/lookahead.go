@@ -0,0 +1,83 @@
+package lexer
+
+import "fmt"
+
+// Checkpoint is a token boundary returned by Mark that the lexer can be
+// rewound to with Reset.
+type Checkpoint int
+
+// Peek returns the token n positions ahead without consuming it; Peek(0)
+// is the token the next call to NextToken will return.
+func (l *Lexer) Peek(n int) Token {
+	for l.first+l.ring.len() <= l.cur+n {
+		l.ring.push(l.scanToken())
+	}
+	return l.ring.at(l.cur + n - l.first)
+}
+
+// Mark returns a checkpoint at the current token boundary. Tokens read
+// after it are kept until the checkpoint is released, so every Mark must
+// be paired with a Release.
+func (l *Lexer) Mark() Checkpoint {
+	l.marks++
+	return Checkpoint(l.cur)
+}
+
+// Reset rewinds the lexer so that NextToken returns the tokens read since
+// c again. c must not have been released.
+func (l *Lexer) Reset(c Checkpoint) {
+	if int(c) < l.first || int(c) > l.cur {
+		panic(fmt.Sprintf("lexer: Reset to unknown checkpoint %d", c))
+	}
+	l.cur = int(c)
+}
+
+// Release tells the lexer that c will no longer be passed to Reset.
+func (l *Lexer) Release(c Checkpoint) {
+	if l.marks == 0 {
+		panic(fmt.Sprintf("lexer: Release of unknown checkpoint %d", c))
+	}
+	l.marks--
+	l.discard()
+}
+
+// discard drops the buffered tokens that have been consumed and can no
+// longer be returned to.
+func (l *Lexer) discard() {
+	if l.marks == 0 && l.cur > l.first {
+		l.ring.drop(l.cur - l.first)
+		l.first = l.cur
+	}
+}
+
+// tokenRing is a growable ring buffer of tokens.
+type tokenRing struct {
+	buf  []Token // len(buf) is zero or a power of two
+	head int     // index of the oldest token in buf
+	n    int     // number of tokens held
+}
+
+func (r *tokenRing) len() int {
+	return r.n
+}
+
+func (r *tokenRing) at(i int) Token {
+	return r.buf[(r.head+i)&(len(r.buf)-1)]
+}
+
+func (r *tokenRing) push(tok Token) {
+	if r.n == len(r.buf) {
+		buf := make([]Token, max(2*len(r.buf), 4))
+		for i := 0; i < r.n; i++ {
+			buf[i] = r.at(i)
+		}
+		r.buf, r.head = buf, 0
+	}
+	r.buf[(r.head+r.n)&(len(r.buf)-1)] = tok
+	r.n++
+}
+
+func (r *tokenRing) drop(n int) {
+	r.head = (r.head + n) & (len(r.buf) - 1)
+	r.n -= n
+}
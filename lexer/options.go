@@ -0,0 +1,69 @@
+package lexer
+
+// Option configures a Lexer.
+type Option func(*Lexer)
+
+// BOMPolicy selects how a Lexer treats a UTF-8 byte order mark at the very
+// start of the input. RFC 8259 forbids emitting one but lets parsers ignore it.
+type BOMPolicy int
+
+const (
+	BOMReject BOMPolicy = iota // Report it as an invalid token
+	BOMStrip                   // Skip it silently
+)
+
+// Dialect is a variant of the JSON syntax.
+type Dialect int
+
+const (
+	DialectJSON  Dialect = iota // RFC 8259 JSON
+	DialectJSON5                // JSON5, see https://spec.json5.org
+	DialectJSONC                // JSON with comments, as in tsconfig.json and VS Code settings
+)
+
+var dialectNames = [...]string{
+	DialectJSON:  "json",
+	DialectJSON5: "json5",
+	DialectJSONC: "jsonc",
+}
+
+func (d Dialect) String() string {
+	if d < 0 || int(d) >= len(dialectNames) {
+		return "unknown"
+	}
+	return dialectNames[d]
+}
+
+// Strict makes the lexer reject input that is not valid UTF-8 and accept
+// only the four whitespace characters JSON defines: space, tab, line feed
+// and carriage return. Without it, or in the JSON5 dialect, vertical tab
+// and form feed are skipped as well.
+func Strict() Option {
+	return func(l *Lexer) {
+		l.strict = true
+	}
+}
+
+// WithDialect sets the JSON syntax the lexer accepts. The default is
+// DialectJSON.
+func WithDialect(d Dialect) Option {
+	return func(l *Lexer) {
+		l.dialect = d
+	}
+}
+
+// KeepComments makes the lexer return the comments of the JSON5 and JSONC
+// dialects as COMMENT tokens instead of skipping them.
+func KeepComments() Option {
+	return func(l *Lexer) {
+		l.comments = true
+	}
+}
+
+// WithBOM sets how a leading byte order mark is handled. The default is
+// BOMReject.
+func WithBOM(policy BOMPolicy) Option {
+	return func(l *Lexer) {
+		l.bom = policy
+	}
+}
@@ -0,0 +1,108 @@
+package lexer
+
+// TokenType identifies what a Token represents.
+type TokenType int
+
+// Token types
+const (
+	TokenInvalid     TokenType = iota // Invalid token
+	TokenEOF                          // End of file/input
+	TokenCurlyOpen                    // Represents `{`
+	TokenCurlyClose                   // Represents `}`
+	TokenSquareOpen                   // Represents `[`
+	TokenSquareClose                  // Represents `]`
+	TokenColon                        // Represents `:`
+	TokenComma                        // Represents `,`
+	TokenString                       // Represents strings
+	TokenNumber                       // Represents digit 0-9 including floats and exponents
+	TokenBool                         // Represents Bool true/false
+	TokenNull                         // Represents Null
+	TokenIdentifier                   // Represents an unquoted object key (JSON5)
+	TokenComment                      // Represents a // or /* */ comment, see KeepComments
+)
+
+var tokenNames = [...]string{
+	TokenInvalid:     "INVALID",
+	TokenEOF:         "EOF",
+	TokenCurlyOpen:   "{",
+	TokenCurlyClose:  "}",
+	TokenSquareOpen:  "[",
+	TokenSquareClose: "]",
+	TokenColon:       ":",
+	TokenComma:       ",",
+	TokenString:      "STRING",
+	TokenNumber:      "NUMBER",
+	TokenBool:        "BOOL",
+	TokenNull:        "NULL",
+	TokenIdentifier:  "IDENTIFIER",
+	TokenComment:     "COMMENT",
+}
+
+// String returns the name used for t in diagnostics: the character itself
+// for structural tokens and an upper-case name for the others.
+func (t TokenType) String() string {
+	if t < 0 || int(t) >= len(tokenNames) {
+		return "UNKNOWN"
+	}
+	return tokenNames[t]
+}
+
+// IsValue reports whether t is a scalar value: a string, number, boolean
+// or null.
+func (t TokenType) IsValue() bool {
+	return t.Kind() != KindNone
+}
+
+// IsStructural reports whether t is one of the six structural characters
+// { } [ ] : and ,.
+func (t TokenType) IsStructural() bool {
+	return t >= TokenCurlyOpen && t <= TokenComma
+}
+
+// IsError reports whether t signals a lexical error.
+func (t TokenType) IsError() bool {
+	return t == TokenInvalid
+}
+
+// Kind returns the kind of value a token of type t holds, or KindNone if
+// it is not a scalar value.
+func (t TokenType) Kind() Kind {
+	switch t {
+	case TokenString:
+		return KindString
+	case TokenNumber:
+		return KindNumber
+	case TokenBool:
+		return KindBool
+	case TokenNull:
+		return KindNull
+	default:
+		return KindNone
+	}
+}
+
+// Kind is the kind of a scalar value.
+type Kind int
+
+const (
+	KindNone   Kind = iota // Not a scalar value
+	KindString             // A string
+	KindNumber             // A number, see Token.Number for integer or float
+	KindBool               // true or false
+	KindNull               // null
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindString:
+		return "string"
+	case KindNumber:
+		return "number"
+	case KindBool:
+		return "bool"
+	case KindNull:
+		return "null"
+	default:
+		return "none"
+	}
+}
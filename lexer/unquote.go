@@ -0,0 +1,173 @@
+package lexer
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// SurrogatePolicy selects what Unquote does with a \u escape that encodes a
+// lone or mismatched UTF-16 surrogate, which JSON syntax allows but which is
+// not a valid Unicode character.
+type SurrogatePolicy int
+
+const (
+	SurrogateError   SurrogatePolicy = iota // Return an error
+	SurrogateReplace                        // Replace it with U+FFFD
+	SurrogateWTF8                           // Keep it, encoded as WTF-8
+)
+
+// Unquote decodes the Literal of a TokenString into the string it denotes,
+// resolving escape sequences and combining UTF-16 surrogate pairs such as
+// \ud83d\ude00 into a single rune.
+func Unquote(s string, policy SurrogatePolicy) (string, error) {
+	return unquote(s, policy, false)
+}
+
+// UnquoteJSON5 is Unquote for the strings and identifiers of JSON5, which
+// also have the escapes \' \v \0 and \xHH, continue a string on the next
+// line after a backslash before a line break, and let a backslash before
+// any other character stand for that character.
+func UnquoteJSON5(s string, policy SurrogatePolicy) (string, error) {
+	return unquote(s, policy, true)
+}
+
+func unquote(s string, policy SurrogatePolicy, json5 bool) (string, error) {
+	if strings.IndexByte(s, '\\') < 0 {
+		return s, nil
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); {
+		if s[i] != '\\' {
+			b.WriteByte(s[i])
+			i++
+			continue
+		}
+		if i+1 >= len(s) {
+			return "", fmt.Errorf("unterminated escape sequence at offset %d", i)
+		}
+
+		switch ch := s[i+1]; ch {
+		case '"', '\\', '/':
+			b.WriteByte(ch)
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case 'u':
+			r, ok := unhex(s, i+2, 4)
+			if !ok {
+				return "", fmt.Errorf("invalid escape sequence '\\u' at offset %d: expected four hex digits", i)
+			}
+			if !utf16.IsSurrogate(r) {
+				b.WriteRune(r)
+				i += 6
+				continue
+			}
+
+			// A high surrogate followed by a low one is a single character.
+			if r < 0xDC00 && i+12 <= len(s) && s[i+6] == '\\' && s[i+7] == 'u' {
+				if r2, ok := unhex(s, i+8, 4); ok {
+					if dec := utf16.DecodeRune(r, r2); dec != utf8.RuneError {
+						b.WriteRune(dec)
+						i += 12
+						continue
+					}
+				}
+			}
+
+			switch policy {
+			case SurrogateReplace:
+				b.WriteRune(utf8.RuneError)
+			case SurrogateWTF8:
+				b.Write([]byte{0xE0 | byte(r>>12), 0x80 | byte(r>>6)&0x3F, 0x80 | byte(r)&0x3F})
+			default:
+				return "", fmt.Errorf("lone surrogate \\u%04x at offset %d", r, i)
+			}
+			i += 6
+			continue
+		default:
+			if !json5 {
+				return "", fmt.Errorf("invalid escape sequence '\\%c' at offset %d", ch, i)
+			}
+			n, err := unescape5(&b, s, i)
+			if err != nil {
+				return "", err
+			}
+			i += n
+			continue
+		}
+		i += 2
+	}
+	return b.String(), nil
+}
+
+// unescape5 decodes an escape sequence at s[i] that only JSON5 has and
+// returns its length.
+func unescape5(b *strings.Builder, s string, i int) (int, error) {
+	switch ch := s[i+1]; {
+	case ch == '\'':
+		b.WriteByte('\'')
+	case ch == 'v':
+		b.WriteByte('\v')
+	case ch == '0' && (i+2 >= len(s) || !isDigit(s[i+2])):
+		b.WriteByte(0)
+	case ch == 'x':
+		r, ok := unhex(s, i+2, 2)
+		if !ok {
+			return 0, fmt.Errorf("invalid escape sequence '\\x' at offset %d: expected two hex digits", i)
+		}
+		b.WriteRune(r)
+		return 4, nil
+	case isDigit(ch):
+		return 0, fmt.Errorf("invalid escape sequence '\\%c' at offset %d", ch, i)
+	case ch == '\n':
+		// A line continuation stands for nothing.
+	case ch == '\r':
+		if i+2 < len(s) && s[i+2] == '\n' {
+			return 3, nil
+		}
+	case ch >= utf8.RuneSelf:
+		r, size := utf8.DecodeRuneInString(s[i+1:])
+		if r != '\u2028' && r != '\u2029' {
+			b.WriteString(s[i+1 : i+1+size])
+		}
+		return 1 + size, nil
+	default:
+		b.WriteByte(ch)
+	}
+	return 2, nil
+}
+
+// unhex decodes the n hex digits starting at s[i].
+func unhex(s string, i, n int) (rune, bool) {
+	if i+n > len(s) {
+		return 0, false
+	}
+
+	var r rune
+	for _, ch := range []byte(s[i : i+n]) {
+		var v byte
+		switch {
+		case isDigit(ch):
+			v = ch - '0'
+		case ch >= 'a' && ch <= 'f':
+			v = ch - 'a' + 10
+		case ch >= 'A' && ch <= 'F':
+			v = ch - 'A' + 10
+		default:
+			return 0, false
+		}
+		r = r<<4 | rune(v)
+	}
+	return r, true
+}
@@ -0,0 +1,95 @@
+package lexer
+
+import (
+	"testing"
+)
+
+func TestUnquote_Escapes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`plain`, "plain"},
+		{`He said, \"hi\"`, `He said, "hi"`},
+		{`\\ \/ \b \f \n \r \t`, "\\ / \b \f \n \r \t"},
+		{`caf\u00e9 é`, "café é"},
+		{`\u0000`, "\x00"},
+		{`\ud83d\ude00`, "😀"},
+		{`a\ud83d\ude00b`, "a😀b"},
+	}
+
+	for _, tt := range tests {
+		got, err := Unquote(tt.input, SurrogateError)
+		if err != nil {
+			t.Errorf("Unquote(%q) returned error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("Unquote(%q) = %q, expected %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestUnquote_SurrogatePolicies(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		replace string
+		wtf8    string
+	}{
+		{"LoneHigh", `a\ud83db`, "a�b", "a\xed\xa0\xbdb"},
+		{"LoneLow", `\ude00`, "�", "\xed\xb8\x80"},
+		{"HighThenHigh", `\ud83d\ud83d\ude00`, "�😀", "\xed\xa0\xbd😀"},
+		{"HighThenLetter", `\ud83dA`, "�A", "\xed\xa0\xbdA"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Unquote(tt.input, SurrogateError); err == nil {
+				t.Errorf("Expected an error for %q", tt.input)
+			}
+			if got, err := Unquote(tt.input, SurrogateReplace); err != nil || got != tt.replace {
+				t.Errorf("SurrogateReplace: got (%q, %v), expected %q", got, err, tt.replace)
+			}
+			if got, err := Unquote(tt.input, SurrogateWTF8); err != nil || got != tt.wtf8 {
+				t.Errorf("SurrogateWTF8: got (%q, %v), expected %q", got, err, tt.wtf8)
+			}
+		})
+	}
+}
+
+func TestUnquote_InvalidEscapes(t *testing.T) {
+	for _, input := range []string{`\x`, `\u12`, `\u12G4`, `abc\`} {
+		if _, err := Unquote(input, SurrogateReplace); err == nil {
+			t.Errorf("Expected Unquote(%q) to fail", input)
+		}
+	}
+}
+
+func TestUnquoteJSON5(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`it\'s`, "it's"},
+		{`\x41\v\0`, "A\v\x00"},
+		{"one \\\ntwo", "one two"},
+		{"one \\\r\ntwo", "one two"},
+		{"one \\\u2028two", "one two"},
+		{`\a\q\ `, "aq "},
+		{`é`, "é"},
+	}
+
+	for _, tt := range tests {
+		got, err := UnquoteJSON5(tt.input, SurrogateError)
+		if err != nil || got != tt.expected {
+			t.Errorf("UnquoteJSON5(%q) = (%q, %v), expected %q", tt.input, got, err, tt.expected)
+		}
+	}
+
+	for _, input := range []string{`\1`, `\01`, `\x4`, `\xZZ`} {
+		if _, err := UnquoteJSON5(input, SurrogateError); err == nil {
+			t.Errorf("Expected UnquoteJSON5(%q) to fail", input)
+		}
+	}
+}
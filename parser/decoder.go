@@ -0,0 +1,363 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+
+	"github.com/HrithikSawant/go-json-parser/lexer"
+)
+
+// TokenKind identifies the kind of a Token read by a Decoder.
+type TokenKind int
+
+const (
+	TokenObjectStart TokenKind = iota
+	TokenObjectEnd
+	TokenArrayStart
+	TokenArrayEnd
+	TokenKey
+	TokenString
+	TokenNumber
+	TokenBool
+	TokenNull
+)
+
+var tokenKindNames = [...]string{
+	TokenObjectStart: "{",
+	TokenObjectEnd:   "}",
+	TokenArrayStart:  "[",
+	TokenArrayEnd:    "]",
+	TokenKey:         "KEY",
+	TokenString:      "STRING",
+	TokenNumber:      "NUMBER",
+	TokenBool:        "BOOL",
+	TokenNull:        "NULL",
+}
+
+func (k TokenKind) String() string {
+	if k < 0 || int(k) >= len(tokenKindNames) {
+		return "UNKNOWN"
+	}
+	return tokenKindNames[k]
+}
+
+// Token is an element of a document as read by a Decoder. Unlike a
+// lexer.Token it tells object keys from string values, and commas and
+// colons are not reported.
+type Token struct {
+	Kind TokenKind
+	// Value is the decoded key or string, the literal of a number, or
+	// "true", "false" or "null". It is empty for delimiters.
+	Value  string
+	Number lexer.NumberKind // for TokenNumber
+	Pos    lexer.Position
+}
+
+// errNoValue is returned by Decoder methods that read a value when the
+// next token is an object key or closes a container.
+var errNoValue = errors.New("next token does not start a value")
+
+// Decoder reads a document one Token at a time, like encoding/json's
+// Decoder.Token, while enforcing the same grammar and options as Parse.
+type Decoder struct {
+	p   *Parser
+	rec tokenRecorder
+	err error // sticky: a syntax error, or io.EOF after the value
+}
+
+// NewDecoder returns a Decoder reading the tokens of l.
+func NewDecoder(l *lexer.Lexer, opts ...Option) *Decoder {
+	d := &Decoder{p: NewParser(l, opts...)}
+	d.p.handler = &d.rec
+	d.p.reset()
+	return d
+}
+
+// Token returns the next token. Once the top-level value is complete and
+// the input has ended, it returns io.EOF; after a syntax error it keeps
+// returning that error.
+func (d *Decoder) Token() (Token, error) {
+	for !d.rec.have {
+		if d.err != nil {
+			return Token{}, d.err
+		}
+		done, err := d.p.step()
+		if err != nil {
+			d.err = err
+		} else if done {
+			d.err = io.EOF
+		}
+	}
+	d.rec.have = false
+	return d.rec.tok, nil
+}
+
+// More reports whether there is another member or element in the object
+// or array being read. It is false at a comma that is not followed by a
+// member or element, so that the next call to Token reports the error.
+func (d *Decoder) More() bool {
+	next := d.peek(0)
+	if next.Type == lexer.TokenComma {
+		switch d.p.top().state {
+		case stateExpectCommaOrEnd, stateArrayCommaOrEnd:
+			next = d.peek(1)
+		default:
+			return false
+		}
+	}
+	switch next.Type {
+	case lexer.TokenCurlyClose, lexer.TokenSquareClose, lexer.TokenComma, lexer.TokenEOF:
+		return false
+	}
+	return d.err == nil
+}
+
+// Diagnostics returns the warnings recorded so far, such as duplicate keys
+// under DuplicateWarn.
+func (d *Decoder) Diagnostics() []Diagnostic {
+	return d.p.Diagnostics()
+}
+
+// checkValue returns an error unless the next token starts a value.
+func (d *Decoder) checkValue() error {
+	if d.err != nil {
+		return d.err
+	}
+	switch d.p.top().state {
+	case stateExpectKeyOrEnd, stateExpectCommaOrEnd, stateDone:
+		return errNoValue
+	}
+	if !d.More() {
+		return errNoValue
+	}
+	return nil
+}
+
+// Skip reads the next value, including everything nested in it, and
+// discards it.
+func (d *Decoder) Skip() error {
+	if err := d.checkValue(); err != nil {
+		return err
+	}
+	depth := len(d.p.stack)
+	if _, err := d.Token(); err != nil {
+		return err
+	}
+	for len(d.p.stack) > depth {
+		if _, err := d.Token(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Value reads the next value as a document tree, as ParseValue does for a
+// whole document.
+func (d *Decoder) Value() (Value, error) {
+	if err := d.checkValue(); err != nil {
+		return nil, err
+	}
+	tree := &treeBuilder{p: d.p, duplicates: d.p.duplicates}
+	d.p.handler = tree
+	defer func() { d.p.handler = &d.rec }()
+
+	for tree.root == nil || len(tree.open) > 0 {
+		if _, err := d.p.step(); err != nil {
+			d.err = err
+			return nil, err
+		}
+	}
+	return tree.root, nil
+}
+
+// Members reads the next value, which must be an object, yielding the key
+// of each member. The loop body may read the member's value with Token,
+// Skip or Value; whatever it leaves unread is skipped before the next key.
+// Breaking out of the loop leaves the rest of the object unread.
+func (d *Decoder) Members() iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		depth, err := d.open(TokenObjectStart)
+		if err != nil {
+			yield("", err)
+			return
+		}
+		for {
+			tok, err := d.Token()
+			if err != nil {
+				yield("", err)
+				return
+			}
+			if tok.Kind == TokenObjectEnd || !yield(tok.Value, nil) {
+				return
+			}
+			if closed, err := d.finish(depth); err != nil {
+				yield("", err)
+				return
+			} else if closed {
+				return
+			}
+		}
+	}
+}
+
+// Elements reads the next value, which must be an array, yielding the
+// index of each element. The loop body may read the element with Token,
+// Skip or Value; whatever it leaves unread is skipped before the next
+// element. Breaking out of the loop leaves the rest of the array unread.
+func (d *Decoder) Elements() iter.Seq2[int, error] {
+	return func(yield func(int, error) bool) {
+		depth, err := d.open(TokenArrayStart)
+		if err != nil {
+			yield(0, err)
+			return
+		}
+		for i := 0; ; i++ {
+			if err := d.skipComma(); err != nil {
+				yield(i, err)
+				return
+			}
+			if !d.More() {
+				// Read the ']', or the error in its place.
+				if _, err := d.Token(); err != nil {
+					yield(i, err)
+				}
+				return
+			}
+			if !yield(i, nil) {
+				return
+			}
+			if closed, err := d.finish(depth); err != nil {
+				yield(i, err)
+				return
+			} else if closed {
+				return
+			}
+		}
+	}
+}
+
+// open reads the token that starts the container iterated by Members or
+// Elements and returns the depth of its frame.
+func (d *Decoder) open(kind TokenKind) (int, error) {
+	if err := d.checkValue(); err != nil {
+		return 0, err
+	}
+	tok, err := d.Token()
+	if err != nil {
+		return 0, err
+	}
+	if tok.Kind != kind {
+		return 0, &ParseError{
+			Pos:   tok.Pos,
+			State: d.p.top().state.String(),
+			Found: d.p.tok,
+			Msg:   fmt.Sprintf("expected '%s' but found %s", kind, tok.Kind),
+		}
+	}
+	return len(d.p.stack), nil
+}
+
+// peek returns the token n positions ahead, not counting comments.
+func (d *Decoder) peek(n int) lexer.Token {
+	for i := 0; ; i++ {
+		tok := d.p.lexer.Peek(i)
+		if tok.Type == lexer.TokenComment {
+			continue
+		}
+		if n == 0 || tok.Type == lexer.TokenEOF {
+			return tok
+		}
+		n--
+	}
+}
+
+// skipComma reads the comma, if any, before the next array element, so
+// that finish can tell from the array's frame whether the element was read.
+func (d *Decoder) skipComma() error {
+	if d.err != nil || d.peek(0).Type != lexer.TokenComma {
+		return nil
+	}
+	if _, err := d.p.step(); err != nil {
+		d.err = err
+		return err
+	}
+	return nil
+}
+
+// finish reads what the loop body left of the current member or element
+// of the container whose frame is at depth. It reports closed if the body
+// read past the end of the container.
+func (d *Decoder) finish(depth int) (closed bool, err error) {
+	for {
+		switch {
+		case len(d.p.stack) < depth:
+			return true, nil
+		case len(d.p.stack) == depth:
+			switch d.p.top().state {
+			case stateExpectCommaOrEnd, stateArrayCommaOrEnd:
+				return false, nil
+			}
+		}
+		if _, err := d.Token(); err != nil {
+			return false, err
+		}
+	}
+}
+
+// tokenRecorder is the Handler through which a Decoder learns the token
+// the parser has just read.
+type tokenRecorder struct {
+	tok  Token
+	have bool
+}
+
+func (r *tokenRecorder) set(kind TokenKind, value string, pos lexer.Position) error {
+	r.tok, r.have = Token{Kind: kind, Value: value, Pos: pos}, true
+	return nil
+}
+
+func (r *tokenRecorder) StartObject(pos lexer.Position) error {
+	return r.set(TokenObjectStart, "", pos)
+}
+
+func (r *tokenRecorder) Key(key string, pos lexer.Position) error {
+	return r.set(TokenKey, key, pos)
+}
+
+func (r *tokenRecorder) EndObject(pos lexer.Position) error {
+	return r.set(TokenObjectEnd, "", pos)
+}
+
+func (r *tokenRecorder) StartArray(pos lexer.Position) error {
+	return r.set(TokenArrayStart, "", pos)
+}
+
+func (r *tokenRecorder) EndArray(pos lexer.Position) error {
+	return r.set(TokenArrayEnd, "", pos)
+}
+
+func (r *tokenRecorder) String(value string, pos lexer.Position) error {
+	return r.set(TokenString, value, pos)
+}
+
+func (r *tokenRecorder) Number(literal string, kind lexer.NumberKind, pos lexer.Position) error {
+	r.set(TokenNumber, literal, pos)
+	r.tok.Number = kind
+	return nil
+}
+
+func (r *tokenRecorder) Bool(value bool, pos lexer.Position) error {
+	if value {
+		return r.set(TokenBool, "true", pos)
+	}
+	return r.set(TokenBool, "false", pos)
+}
+
+func (r *tokenRecorder) Null(pos lexer.Position) error {
+	return r.set(TokenNull, "null", pos)
+}
+
+// Error is not called, as the Decoder does not go through parse.
+func (r *tokenRecorder) Error(err *ParseError) {}
@@ -0,0 +1,268 @@
+package parser
+
+import (
+	"errors"
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/HrithikSawant/go-json-parser/lexer"
+)
+
+func TestDecoder_Token(t *testing.T) {
+	d := NewDecoder(lexer.NewLexer(`{"a": ["x", 1.5, true, null], "b!": {}}`))
+
+	expected := []Token{
+		{Kind: TokenObjectStart},
+		{Kind: TokenKey, Value: "a"},
+		{Kind: TokenArrayStart},
+		{Kind: TokenString, Value: "x"},
+		{Kind: TokenNumber, Value: "1.5", Number: lexer.NumberFloat},
+		{Kind: TokenBool, Value: "true"},
+		{Kind: TokenNull, Value: "null"},
+		{Kind: TokenArrayEnd},
+		{Kind: TokenKey, Value: "b!"},
+		{Kind: TokenObjectStart},
+		{Kind: TokenObjectEnd},
+		{Kind: TokenObjectEnd},
+	}
+	for i, want := range expected {
+		tok, err := d.Token()
+		if err != nil {
+			t.Fatalf("Token %d: unexpected error %v", i, err)
+		}
+		tok.Pos = lexer.Position{}
+		if tok != want {
+			t.Errorf("Token %d: got %+v, expected %+v", i, tok, want)
+		}
+	}
+
+	for range 2 {
+		if _, err := d.Token(); err != io.EOF {
+			t.Errorf("Expected io.EOF after the value, got %v", err)
+		}
+	}
+}
+
+func TestDecoder_SyntaxError(t *testing.T) {
+	d := NewDecoder(lexer.NewLexer(`[1 2]`))
+	var err error
+	for err == nil {
+		_, err = d.Token()
+	}
+
+	var perr *ParseError
+	if !errors.As(err, &perr) || perr.Pos.Offset != 3 {
+		t.Fatalf("Expected a ParseError at offset 3, got %v", err)
+	}
+	if _, again := d.Token(); again != err {
+		t.Errorf("Expected the error to be sticky, got %v", again)
+	}
+}
+
+func TestDecoder_MoreAndSkip(t *testing.T) {
+	d := NewDecoder(lexer.NewLexer(`[{"deep": [[1], {"x": 2}]}, "keep", []]`))
+	if tok, _ := d.Token(); tok.Kind != TokenArrayStart {
+		t.Fatalf("Expected '[', got %v", tok.Kind)
+	}
+
+	if !d.More() {
+		t.Fatal("Expected More before the first element")
+	}
+	if err := d.Skip(); err != nil {
+		t.Fatalf("Skip failed: %v", err)
+	}
+	if tok, err := d.Token(); err != nil || tok.Value != "keep" {
+		t.Fatalf("Expected \"keep\" after Skip, got %+v, %v", tok, err)
+	}
+	if err := d.Skip(); err != nil {
+		t.Fatalf("Skip failed: %v", err)
+	}
+	if d.More() {
+		t.Error("Expected no more elements")
+	}
+	if err := d.Skip(); err == nil {
+		t.Error("Expected Skip to fail at the end of the array")
+	}
+	if tok, err := d.Token(); err != nil || tok.Kind != TokenArrayEnd {
+		t.Errorf("Expected ']', got %+v, %v", tok, err)
+	}
+}
+
+func TestDecoder_Members(t *testing.T) {
+	d := NewDecoder(lexer.NewLexer(`{"id": 7, "tags": ["a", "b"], "meta": {"x": 1}, "name": "n"}`))
+
+	var keys []string
+	for key, err := range d.Members() {
+		if err != nil {
+			t.Fatalf("Members failed: %v", err)
+		}
+		keys = append(keys, key)
+		switch key {
+		case "tags":
+			// Read only the start of the array; the rest is skipped.
+			if tok, _ := d.Token(); tok.Kind != TokenArrayStart {
+				t.Errorf("Expected '[', got %v", tok.Kind)
+			}
+		case "name":
+			v, err := d.Value()
+			if err != nil {
+				t.Fatalf("Value failed: %v", err)
+			}
+			if s, ok := v.(*String); !ok || s.Value != "n" {
+				t.Errorf("Expected name \"n\", got %#v", v)
+			}
+		}
+	}
+
+	if !slices.Equal(keys, []string{"id", "tags", "meta", "name"}) {
+		t.Errorf("Unexpected keys %v", keys)
+	}
+	if _, err := d.Token(); err != io.EOF {
+		t.Errorf("Expected io.EOF after the object, got %v", err)
+	}
+}
+
+func TestDecoder_Elements(t *testing.T) {
+	d := NewDecoder(lexer.NewLexer(`[{"n": 1}, {"n": 2}, {"n": 3}]`))
+
+	var sum int64
+	count := 0
+	for i, err := range d.Elements() {
+		if err != nil {
+			t.Fatalf("Elements failed: %v", err)
+		}
+		if i != count {
+			t.Errorf("Expected index %d, got %d", count, i)
+		}
+		count++
+
+		v, err := d.Value()
+		if err != nil {
+			t.Fatalf("Value failed: %v", err)
+		}
+		n, _ := v.(*Object).Get("n")
+		i64, _ := n.(*Number).Int64()
+		sum += i64
+	}
+
+	if count != 3 || sum != 6 {
+		t.Errorf("Expected 3 elements summing to 6, got %d summing to %d", count, sum)
+	}
+}
+
+func TestDecoder_UnreadElements(t *testing.T) {
+	d := NewDecoder(lexer.NewLexer(`[1, [2, 3], {"a": [4]}, "five"]`))
+	count := 0
+	for _, err := range d.Elements() {
+		if err != nil {
+			t.Fatalf("Elements failed: %v", err)
+		}
+		count++
+	}
+	if count != 4 {
+		t.Errorf("Expected 4 elements, got %d", count)
+	}
+	if _, err := d.Token(); err != io.EOF {
+		t.Errorf("Expected io.EOF after the array, got %v", err)
+	}
+}
+
+func TestDecoder_IteratorErrors(t *testing.T) {
+	d := NewDecoder(lexer.NewLexer(`[1, 2,]`))
+	var last error
+	for _, err := range d.Elements() {
+		last = err
+	}
+	if last == nil {
+		t.Error("Expected the trailing comma to be reported")
+	}
+
+	d = NewDecoder(lexer.NewLexer(`[1]`))
+	for _, err := range d.Members() {
+		last = err
+	}
+	var perr *ParseError
+	if !errors.As(last, &perr) || perr.Msg != "expected '{' but found [" {
+		t.Errorf("Expected a ParseError for the array, got %v", last)
+	}
+}
+
+func TestDecoder_EmptyContainers(t *testing.T) {
+	for _, err := range NewDecoder(lexer.NewLexer(`{}`)).Members() {
+		t.Errorf("Expected no members, got error %v", err)
+	}
+	for _, err := range NewDecoder(lexer.NewLexer(`[]`)).Elements() {
+		t.Errorf("Expected no elements, got error %v", err)
+	}
+}
+
+// Comments kept by the lexer are passed over.
+func TestDecoder_Comments(t *testing.T) {
+	lex := lexer.NewLexer("[1, /* two */ 2 // end\n, 3]", lexer.WithDialect(lexer.DialectJSONC), lexer.KeepComments())
+	d := NewDecoder(lex)
+	if _, err := d.Token(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var got []string
+	for d.More() {
+		tok, err := d.Token()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		got = append(got, tok.Value)
+	}
+	if !slices.Equal(got, []string{"1", "2", "3"}) {
+		t.Errorf("Got %v, expected [1 2 3]", got)
+	}
+}
+
+// A doubled comma ends the iteration with the error instead of yielding
+// a phantom member or element.
+func TestDecoder_DoubledComma(t *testing.T) {
+	d := NewDecoder(lexer.NewLexer(`[1, 2,, 3]`))
+	var indexes []int
+	var err error
+	for i, e := range d.Elements() {
+		if e != nil {
+			err = e
+			break
+		}
+		indexes = append(indexes, i)
+		if _, e := d.Value(); e != nil {
+			t.Fatalf("Unexpected error: %v", e)
+		}
+	}
+	if !slices.Equal(indexes, []int{0, 1}) || err == nil {
+		t.Errorf("Got indexes %v and error %v, expected [0 1] and an error", indexes, err)
+	}
+
+	d = NewDecoder(lexer.NewLexer(`{"a": 1,, "b": 2}`))
+	var keys []string
+	err = nil
+	for key, e := range d.Members() {
+		if e != nil {
+			err = e
+			break
+		}
+		keys = append(keys, key)
+		if _, e := d.Value(); e != nil {
+			t.Fatalf("Unexpected error: %v", e)
+		}
+	}
+	if !slices.Equal(keys, []string{"a"}) || err == nil {
+		t.Errorf("Got keys %v and error %v, expected [a] and an error", keys, err)
+	}
+
+	// Reading tokens by hand, More stops at the doubled comma.
+	d = NewDecoder(lexer.NewLexer(`[1,,2]`))
+	d.Token()
+	d.Token()
+	if d.More() {
+		t.Error("Expected no more elements at the doubled comma")
+	}
+	if _, err := d.Token(); err == nil {
+		t.Error("Expected the doubled comma to be an error")
+	}
+}
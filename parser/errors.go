@@ -0,0 +1,64 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/HrithikSawant/go-json-parser/lexer"
+)
+
+// ParseError describes why the parser rejected its input.
+type ParseError struct {
+	Pos      lexer.Position    // where the offending token starts
+	State    string            // parser state the token was read in, e.g. "ExpectColon"
+	Found    lexer.Token       // the offending token
+	Expected []lexer.TokenType // the tokens that would have been accepted
+	Msg      string            // human-readable description
+	Related  *lexer.Position   // another place the error refers to, if any
+	Err      error             // the *lexer.Error behind an invalid token, or a sentinel such as ErrTooDeep
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Msg)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorList is the error returned by a parser configured with Recover:
+// every syntax error found, in input order.
+type ErrorList []*ParseError
+
+func (l ErrorList) Error() string {
+	switch len(l) {
+	case 0:
+		return "no errors"
+	case 1:
+		return l[0].Error()
+	case 2:
+		return fmt.Sprintf("%s (and 1 more error)", l[0])
+	}
+	return fmt.Sprintf("%s (and %d more errors)", l[0], len(l)-1)
+}
+
+// Unwrap returns the errors, so that errors.As finds the first
+// *ParseError.
+func (l ErrorList) Unwrap() []error {
+	errs := make([]error, len(l))
+	for i, e := range l {
+		errs[i] = e
+	}
+	return errs
+}
+
+// Diagnostic is a problem the parser tolerated, reported by
+// Parser.Diagnostics.
+type Diagnostic struct {
+	Pos     lexer.Position  // where the problem is
+	Related *lexer.Position // another place the diagnostic refers to, if any
+	Msg     string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s", d.Pos, d.Msg)
+}
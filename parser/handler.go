@@ -0,0 +1,80 @@
+package parser
+
+import (
+	"errors"
+
+	"github.com/HrithikSawant/go-json-parser/lexer"
+)
+
+// Handler receives the structure of a document as the parser reads it,
+// without a tree being built. Each method is given the position of the
+// token it reports; strings and keys arrive with their escapes decoded.
+//
+// A method that returns an error stops the parse. Returning ErrStop ends it
+// quietly; any other error is wrapped in the *ParseError that Walk returns.
+//
+// Error is called with each syntax or read error the parser finds, before
+// Walk returns it, so that a handler can discard what it made of the
+// events so far. It is not called for errors the handler itself returned.
+type Handler interface {
+	StartObject(pos lexer.Position) error
+	Key(key string, pos lexer.Position) error
+	EndObject(pos lexer.Position) error
+	StartArray(pos lexer.Position) error
+	EndArray(pos lexer.Position) error
+	String(value string, pos lexer.Position) error
+	Number(literal string, kind lexer.NumberKind, pos lexer.Position) error
+	Bool(value bool, pos lexer.Position) error
+	Null(pos lexer.Position) error
+	Error(err *ParseError)
+}
+
+// ErrStop is returned by a Handler to end the parse early. Walk then
+// returns nil, and the rest of the input is not read.
+var ErrStop = errors.New("stop parsing")
+
+// Walk reads a JSON value like Parse and reports its structure to h as it
+// goes. Events before a syntax error have already been delivered when Walk
+// returns it.
+func (p *Parser) Walk(h Handler) error {
+	p.handler = h
+	defer func() { p.handler = nil }()
+
+	err := p.parse()
+	if errors.Is(err, ErrStop) {
+		if len(p.errs) > 0 {
+			return p.errs
+		}
+		return nil
+	}
+	return err
+}
+
+// report tells the handler about err if it is a problem found in the
+// input.
+func (p *Parser) report(err error) {
+	var perr *ParseError
+	if p.handler != nil && errors.As(err, &perr) && !fromHandler(perr) {
+		p.handler.Error(perr)
+	}
+}
+
+// fromHandler reports whether err carries an error returned by a Handler
+// rather than a problem found in the input.
+func fromHandler(err *ParseError) bool {
+	if err.Err == nil || errors.Is(err.Err, ErrTooDeep) || errors.Is(err.Err, ErrDuplicateKey) {
+		return false
+	}
+	var lexErr *lexer.Error
+	return !errors.As(err.Err, &lexErr)
+}
+
+// handled turns the error a Handler returned for tok into a ParseError.
+func (p *Parser) handled(tok lexer.Token, err error) error {
+	if err == nil {
+		return nil
+	}
+	e := p.fail(tok, "%v", err)
+	e.Err = err
+	return e
+}
@@ -0,0 +1,138 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/HrithikSawant/go-json-parser/lexer"
+)
+
+// recorder is a Handler that logs every event it receives. If err is set,
+// it returns err for event number stopAt.
+type recorder struct {
+	events []string
+	stopAt int
+	err    error
+}
+
+func (r *recorder) log(format string, args ...any) error {
+	r.events = append(r.events, fmt.Sprintf(format, args...))
+	if r.err != nil && len(r.events) == r.stopAt {
+		return r.err
+	}
+	return nil
+}
+
+func (r *recorder) StartObject(pos lexer.Position) error { return r.log("{@%d", pos.Offset) }
+func (r *recorder) Key(key string, pos lexer.Position) error {
+	return r.log("key %s@%d", key, pos.Offset)
+}
+func (r *recorder) EndObject(pos lexer.Position) error  { return r.log("}@%d", pos.Offset) }
+func (r *recorder) StartArray(pos lexer.Position) error { return r.log("[@%d", pos.Offset) }
+func (r *recorder) EndArray(pos lexer.Position) error   { return r.log("]@%d", pos.Offset) }
+func (r *recorder) String(value string, pos lexer.Position) error {
+	return r.log("string %s@%d", value, pos.Offset)
+}
+func (r *recorder) Number(literal string, kind lexer.NumberKind, pos lexer.Position) error {
+	return r.log("number %s %v@%d", literal, kind == lexer.NumberFloat, pos.Offset)
+}
+func (r *recorder) Bool(value bool, pos lexer.Position) error {
+	return r.log("bool %v@%d", value, pos.Offset)
+}
+func (r *recorder) Null(pos lexer.Position) error { return r.log("null@%d", pos.Offset) }
+func (r *recorder) Error(err *ParseError)         { r.log("error %s@%d", err.Msg, err.Pos.Offset) }
+
+func TestWalk_Events(t *testing.T) {
+	input := `{"ab": [1, 2.5, true], "c": {"d": null}, "e": "x\ny"}`
+	var r recorder
+	if err := NewParser(lexer.NewLexer(input)).Walk(&r); err != nil {
+		t.Fatalf("Walk failed: %v", err)
+	}
+
+	expected := []string{
+		"{@0",
+		"key ab@1",
+		"[@7", "number 1 false@8", "number 2.5 true@11", "bool true@16", "]@20",
+		"key c@23",
+		"{@28", "key d@29", "null@34", "}@38",
+		"key e@41",
+		"string x\ny@46",
+		"}@52",
+	}
+	if !slices.Equal(r.events, expected) {
+		t.Errorf("Unexpected events:\n got %q\nwant %q", r.events, expected)
+	}
+}
+
+func TestWalk_SyntaxError(t *testing.T) {
+	var r recorder
+	err := NewParser(lexer.NewLexer(`[1, 2,]`)).Walk(&r)
+
+	var perr *ParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("Expected a *ParseError, got %v", err)
+	}
+	expected := []string{"[@0", "number 1 false@1", "number 2 false@4", "error " + perr.Msg + "@6"}
+	if !slices.Equal(r.events, expected) {
+		t.Errorf("Expected events %q before the error, got %q", expected, r.events)
+	}
+}
+
+func TestWalk_Stop(t *testing.T) {
+	// The input is invalid after the point where the handler stops.
+	r := recorder{stopAt: 2, err: ErrStop}
+	if err := NewParser(lexer.NewLexer(`{"a": 1, "b": oops`)).Walk(&r); err != nil {
+		t.Fatalf("Expected ErrStop to end the parse quietly, got %v", err)
+	}
+	if len(r.events) != 2 {
+		t.Errorf("Expected 2 events, got %q", r.events)
+	}
+}
+
+func TestWalk_HandlerError(t *testing.T) {
+	errLimit := errors.New("too many values")
+	r := recorder{stopAt: 3, err: errLimit}
+	err := NewParser(lexer.NewLexer(`[true, false, null]`)).Walk(&r)
+
+	if !errors.Is(err, errLimit) {
+		t.Fatalf("Expected an error wrapping the handler's, got %v", err)
+	}
+	var perr *ParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("Expected a *ParseError, got %T", err)
+	}
+	if perr.Pos.Offset != 7 {
+		t.Errorf("Expected the error at offset 7, got %d", perr.Pos.Offset)
+	}
+	if len(r.events) != 3 {
+		t.Errorf("Expected no error event for the handler's own error, got %q", r.events)
+	}
+}
+
+// With Recover, the handler hears of each error where it is found.
+func TestWalk_ErrorEvents(t *testing.T) {
+	var r recorder
+	err := NewParser(lexer.NewLexer(`[1 2, @, 3`), Recover(0)).Walk(&r)
+
+	var errs ErrorList
+	if !errors.As(err, &errs) || len(errs) != 3 {
+		t.Fatalf("Expected 3 errors, got %v", err)
+	}
+	var events []string
+	for _, e := range r.events {
+		if strings.HasPrefix(e, "error ") {
+			events = append(events, e)
+		}
+	}
+	if len(events) != len(errs) {
+		t.Fatalf("Expected an error event per error, got %q", r.events)
+	}
+	for i, e := range errs {
+		if expected := fmt.Sprintf("error %s@%d", e.Msg, e.Pos.Offset); events[i] != expected {
+			t.Errorf("Event %d - got %q, expected %q", i, events[i], expected)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"iter"
+
+	"github.com/HrithikSawant/go-json-parser/lexer"
+)
+
+// Record is one document of a stream: a line read by NDJSONReader or a
+// text read by SeqReader.
+type Record struct {
+	Line        int   // line number the document starts on, starting at 1
+	Value       Value // the document; nil if Err is set and the parser does not recover
+	Err         error // why it is not a valid document
+	Diagnostics []Diagnostic
+}
+
+// RecordSummary counts the records a reader has read.
+type RecordSummary struct {
+	Valid   int
+	Invalid int
+	Blank   int // blank lines or texts that were skipped
+}
+
+// NDJSONReader reads newline-delimited JSON, also known as JSON Lines: a
+// stream of documents, one per line, each ending in "\n" or "\r\n". Every
+// line is parsed on its own, so an invalid one does not affect the lines
+// after it. Positions in records refer to the whole stream.
+type NDJSONReader struct {
+	r         *bufio.Reader
+	lex       *lexer.Lexer
+	p         *Parser
+	skipBlank bool
+	pos       lexer.Position // start of the next line
+	summary   RecordSummary
+	err       error // read error
+}
+
+// NewNDJSONReader returns a reader of the lines of r. The lexer l, which
+// must have been created by lexer.NewBytesLexer, is reset for every line
+// and supplies the lexer options; opts configure the parser of each line.
+func NewNDJSONReader(r io.Reader, l *lexer.Lexer, opts ...Option) *NDJSONReader {
+	return &NDJSONReader{
+		r:   bufio.NewReader(r),
+		lex: l,
+		p:   NewParser(l, opts...),
+		pos: lexer.Position{Line: 1, Column: 1},
+	}
+}
+
+// SkipBlankLines makes the reader pass over lines that are empty or hold
+// only whitespace instead of reporting them as invalid.
+func (n *NDJSONReader) SkipBlankLines() {
+	n.skipBlank = true
+}
+
+// Records returns an iterator over the records of the remaining lines. It
+// stops at the end of the input or at a read error, which Err returns.
+func (n *NDJSONReader) Records() iter.Seq[Record] {
+	return func(yield func(Record) bool) {
+		for {
+			rec, ok := n.next()
+			if !ok || !yield(rec) {
+				return
+			}
+		}
+	}
+}
+
+// next reads and parses the next line that is not skipped.
+func (n *NDJSONReader) next() (Record, bool) {
+	for n.err == nil {
+		line, err := n.r.ReadBytes('\n')
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				n.err = err
+				return Record{}, false
+			}
+			if len(line) == 0 {
+				return Record{}, false
+			}
+		}
+
+		pos := n.pos
+		n.pos = lexer.Position{Offset: pos.Offset + len(line), Line: pos.Line + 1, Column: 1}
+		line = bytes.TrimSuffix(bytes.TrimSuffix(line, []byte("\n")), []byte("\r"))
+
+		if n.skipBlank && len(bytes.TrimSpace(line)) == 0 {
+			n.summary.Blank++
+			continue
+		}
+
+		n.lex.ResetBytesAt(line, pos)
+		v, err := n.p.ParseValue()
+		if err != nil {
+			n.summary.Invalid++
+		} else {
+			n.summary.Valid++
+		}
+		return Record{Line: pos.Line, Value: v, Err: err, Diagnostics: n.p.Diagnostics()}, true
+	}
+	return Record{}, false
+}
+
+// Err returns the error that stopped reading the input, if any.
+func (n *NDJSONReader) Err() error {
+	return n.err
+}
+
+// Summary returns the counts of the records read so far.
+func (n *NDJSONReader) Summary() RecordSummary {
+	return n.summary
+}
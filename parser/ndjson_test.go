@@ -0,0 +1,86 @@
+package parser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/HrithikSawant/go-json-parser/lexer"
+)
+
+func TestNDJSONReader(t *testing.T) {
+	input := "{\"a\": 1}\r\n[1, 2]\n\n{\"b\": }\n\"last\""
+	n := NewNDJSONReader(strings.NewReader(input), lexer.NewBytesLexer(nil))
+
+	var lines []int
+	var errs []string
+	for rec := range n.Records() {
+		lines = append(lines, rec.Line)
+		if rec.Err != nil {
+			errs = append(errs, rec.Err.Error())
+		} else if rec.Value == nil {
+			t.Errorf("Line %d: expected a value", rec.Line)
+		}
+	}
+	if n.Err() != nil {
+		t.Fatalf("Unexpected read error: %v", n.Err())
+	}
+
+	if got := strings.Join(errs, "\n"); got != "line 3, column 1: unexpected end of input\n"+
+		"line 4, column 7: unexpected '}' in state ExpectValue" {
+		t.Errorf("Unexpected errors:\n%s", got)
+	}
+	if len(lines) != 5 || lines[4] != 5 {
+		t.Errorf("Got lines %v, expected 1 to 5", lines)
+	}
+	if s := n.Summary(); s != (RecordSummary{Valid: 3, Invalid: 2}) {
+		t.Errorf("Got summary %+v", s)
+	}
+}
+
+func TestNDJSONReader_SkipBlankLines(t *testing.T) {
+	n := NewNDJSONReader(strings.NewReader("1\n\n  \t\n2\n"), lexer.NewBytesLexer(nil))
+	n.SkipBlankLines()
+
+	var lines []int
+	for rec := range n.Records() {
+		if rec.Err != nil {
+			t.Errorf("Unexpected error: %v", rec.Err)
+		}
+		lines = append(lines, rec.Line)
+	}
+	if len(lines) != 2 || lines[0] != 1 || lines[1] != 4 {
+		t.Errorf("Got lines %v, expected [1 4]", lines)
+	}
+	if s := n.Summary(); s != (RecordSummary{Valid: 2, Blank: 2}) {
+		t.Errorf("Got summary %+v", s)
+	}
+}
+
+// Each line gets the parser options and its own diagnostics.
+func TestNDJSONReader_Options(t *testing.T) {
+	input := "{\"a\": 1, \"a\": 2}\n{\"a\": 1}\n"
+	n := NewNDJSONReader(strings.NewReader(input), lexer.NewBytesLexer(nil), WithDuplicateKeys(DuplicateWarn))
+
+	var diags []int
+	for rec := range n.Records() {
+		diags = append(diags, len(rec.Diagnostics))
+		if rec.Line == 1 && rec.Diagnostics[0].Pos.Line != 1 {
+			t.Errorf("Expected the diagnostic on line 1, got %s", rec.Diagnostics[0])
+		}
+	}
+	if len(diags) != 2 || diags[0] != 1 || diags[1] != 0 {
+		t.Errorf("Got diagnostic counts %v, expected [1 0]", diags)
+	}
+}
+
+func TestNDJSONReader_ReadError(t *testing.T) {
+	r := iotest.TimeoutReader(strings.NewReader("1\n2\n"))
+	n := NewNDJSONReader(r, lexer.NewBytesLexer(nil))
+	for range n.Records() {
+	}
+	if !errors.Is(n.Err(), iotest.ErrTimeout) {
+		t.Errorf("Expected the read error, got %v", n.Err())
+	}
+}
@@ -0,0 +1,94 @@
+package parser
+
+import "github.com/HrithikSawant/go-json-parser/lexer"
+
+// Option configures a Parser.
+type Option func(*Parser)
+
+// WithTracer makes the parser report every token it reads to t. Parsers
+// are silent by default.
+func WithTracer(t Tracer) Option {
+	return func(p *Parser) {
+		p.tracer = t
+	}
+}
+
+// WithSurrogatePolicy sets how ParseValue decodes \u escapes of lone UTF-16
+// surrogates. The default is lexer.SurrogateReplace, as in encoding/json.
+// Under lexer.SurrogateError, Parse rejects them as well.
+func WithSurrogatePolicy(policy lexer.SurrogatePolicy) Option {
+	return func(p *Parser) {
+		p.surrogates = policy
+	}
+}
+
+// RequireObjectOrArray rejects documents whose top-level value is not an
+// object or array, as RFC 4627 did. RFC 8259 allows any value.
+func RequireObjectOrArray() Option {
+	return func(p *Parser) {
+		p.containerRoot = true
+	}
+}
+
+// MaxDepth limits how deeply objects and arrays may be nested; deeper input
+// fails with an error wrapping ErrTooDeep. A limit of zero or less removes
+// the limit. The default is DefaultMaxDepth.
+func MaxDepth(n int) Option {
+	return func(p *Parser) {
+		p.maxDepth = n
+	}
+}
+
+// Recover makes the parser continue after a syntax error instead of
+// stopping at it. It skips to the next ',', '}' or ']' of an open object or
+// array, or assumes a missing ',' or ':', and reports every error it found
+// as an ErrorList. ParseValue then returns the tree of what it could read
+// along with the errors. Parsing stops after maxErrors errors; zero or less
+// means no limit. Read errors and errors from a Handler still stop the
+// parse at once. A Decoder does not recover.
+func Recover(maxErrors int) Option {
+	return func(p *Parser) {
+		p.recovery = true
+		p.maxErrors = maxErrors
+	}
+}
+
+// TrailingCommaPolicy selects what the parser does with a comma after the
+// last member of an object or element of an array, as in [1, 2,].
+type TrailingCommaPolicy int
+
+const (
+	TrailingCommaReject TrailingCommaPolicy = iota // Fail with a syntax error
+	TrailingCommaAllow                             // Accept them, as VS Code does for JSONC files
+	TrailingCommaWarn                              // Accept them and record a Diagnostic at the comma
+)
+
+// WithTrailingCommas sets the policy for trailing commas. The default is
+// TrailingCommaAllow for the JSON5 dialect, which permits them, and
+// TrailingCommaReject otherwise.
+func WithTrailingCommas(policy TrailingCommaPolicy) Option {
+	return func(p *Parser) {
+		p.commas = policy
+	}
+}
+
+// DuplicateKeyPolicy selects what the parser does when an object repeats a
+// key. Keys are compared after decoding escapes, so "a" and "\u0061" are
+// the same key.
+type DuplicateKeyPolicy int
+
+const (
+	DuplicateKeepAll   DuplicateKeyPolicy = iota // Accept them and keep every member in the tree
+	DuplicateReject                              // Fail with an error wrapping ErrDuplicateKey
+	DuplicateWarn                                // Accept them and record a Diagnostic
+	DuplicateFirstWins                           // Keep only the first member in the tree
+	DuplicateLastWins                            // Keep only the last value, at the first member's place
+)
+
+// WithDuplicateKeys sets the policy for repeated object keys. The default
+// is DuplicateKeepAll.
+func WithDuplicateKeys(policy DuplicateKeyPolicy) Option {
+	return func(p *Parser) {
+		p.duplicates = policy
+	}
+}
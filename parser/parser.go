@@ -1,13 +1,29 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/HrithikSawant/go-json-parser/lexer"
 )
 
 type Parser struct {
-	lexer *lexer.Lexer
+	lexer         *lexer.Lexer
+	tracer        Tracer
+	surrogates    lexer.SurrogatePolicy
+	json5         bool    // the lexer reads JSON5
+	containerRoot bool    // see RequireObjectOrArray
+	maxDepth      int     // see MaxDepth
+	stack         []frame // the top level and the open objects and arrays
+	duplicates    DuplicateKeyPolicy
+	commas        TrailingCommaPolicy
+	handler       Handler     // set by Walk and ParseValue
+	tok           lexer.Token // the token being handled
+	dupKey        bool        // the last key repeats one in its object
+	diagnostics   []Diagnostic
+	recovery      bool      // see Recover
+	maxErrors     int       // see Recover
+	errs          ErrorList // the errors recovered from
 }
 
 type parserState int
@@ -51,197 +67,521 @@ func (s parserState) String() string {
 	}
 }
 
-func NewParser(l *lexer.Lexer) *Parser {
-	return &Parser{lexer: l}
+// valueTokens are the tokens that can start a value.
+var valueTokens = []lexer.TokenType{
+	lexer.TokenCurlyOpen, lexer.TokenSquareOpen,
+	lexer.TokenString, lexer.TokenNumber, lexer.TokenBool, lexer.TokenNull,
 }
 
-// Parse starts parsing from the top-level JSON object.
-func (p *Parser) Parse() bool {
-	state := stateStart
-
-	tok := p.lexer.NextToken()
-	fmt.Printf("DEBUG: State = %-20s | Token = %-10s | Literal = %s\n", state, tok.Type, tok.Literal)
-
-	// Accept top-level objects or arrays
-	if tok.Type == lexer.TokenCurlyOpen {
-		if !p.parseObject() {
-			return false
+// expectedTokens returns the tokens the parser accepts in state s.
+// afterComma is set when the previous token was a comma, which rules out
+// closing the container.
+func (p *Parser) expectedTokens(s parserState, afterComma bool) []lexer.TokenType {
+	switch s {
+	case stateStart:
+		if p.containerRoot {
+			return []lexer.TokenType{lexer.TokenCurlyOpen, lexer.TokenSquareOpen}
 		}
-	} else if tok.Type == lexer.TokenSquareOpen {
-		if !p.parseArray() {
-			return false
+		return valueTokens
+	case stateExpectKeyOrEnd:
+		keys := []lexer.TokenType{lexer.TokenString}
+		if p.json5 {
+			keys = append(keys, lexer.TokenIdentifier)
 		}
-	} else {
-		fmt.Println("DEBUG: JSON must start with '{' or '['")
-		return false
+		if afterComma && p.commas == TrailingCommaReject {
+			return keys
+		}
+		return append(keys, lexer.TokenCurlyClose)
+	case stateExpectColon:
+		return []lexer.TokenType{lexer.TokenColon}
+	case stateExpectValue:
+		return valueTokens
+	case stateExpectCommaOrEnd:
+		return []lexer.TokenType{lexer.TokenComma, lexer.TokenCurlyClose}
+	case stateDone:
+		return []lexer.TokenType{lexer.TokenEOF}
+	case stateArrayStart:
+		return []lexer.TokenType{lexer.TokenSquareOpen}
+	case stateArrayValueOrEnd:
+		if afterComma && p.commas == TrailingCommaReject {
+			return valueTokens
+		}
+		return append(valueTokens[:len(valueTokens):len(valueTokens)], lexer.TokenSquareClose)
+	case stateArrayCommaOrEnd:
+		return []lexer.TokenType{lexer.TokenComma, lexer.TokenSquareClose}
+	default:
+		return nil
 	}
+}
 
-	state = stateDone
-	tok = p.lexer.NextToken()
-	fmt.Printf("DEBUG: State = %-20s | Token = %-10s | Literal = %s\n", state, tok.Type, tok.Literal)
+// DefaultMaxDepth is the nesting depth a Parser allows unless configured
+// otherwise with MaxDepth.
+const DefaultMaxDepth = 10000
 
-	if tok.Type != lexer.TokenEOF {
-		fmt.Println("DEBUG: Extra tokens after end of object")
-		return false
-	}
+// ErrTooDeep is wrapped by the ParseError returned for input nested deeper
+// than the parser's maximum depth.
+var ErrTooDeep = errors.New("nesting too deep")
 
-	return true
+// ErrDuplicateKey is wrapped by the ParseError returned for a repeated
+// object key under DuplicateReject.
+var ErrDuplicateKey = errors.New("duplicate key")
+
+func NewParser(l *lexer.Lexer, opts ...Option) *Parser {
+	p := &Parser{lexer: l, surrogates: lexer.SurrogateReplace, maxDepth: DefaultMaxDepth}
+	p.json5 = l.Dialect() == lexer.DialectJSON5
+	if p.json5 {
+		p.commas = TrailingCommaAllow
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
 }
 
-func (p *Parser) parseArray() bool {
-	state := stateArrayValueOrEnd
-	justSawComma := false
+// fail returns a ParseError for tok, read in the state of the innermost
+// frame.
+func (p *Parser) fail(tok lexer.Token, format string, args ...any) *ParseError {
+	f := p.top()
+	err := &ParseError{
+		Pos:      tok.Pos,
+		State:    f.state.String(),
+		Found:    tok,
+		Expected: p.expectedTokens(f.state, f.afterComma),
+		Msg:      fmt.Sprintf(format, args...),
+	}
+	if tok.Err != nil {
+		// Point at the offending character rather than the token start.
+		err.Pos = tok.Err.Pos
+		err.Err = tok.Err
+	}
+	return err
+}
 
-	for {
-		tok := p.lexer.NextToken()
-		fmt.Printf("DEBUG: State = %-20s | Token = %-10s | Literal = %s\n", state, tok.Type, tok.Literal)
+// unexpected returns the ParseError for a token that is not allowed in the
+// current state.
+func (p *Parser) unexpected(tok lexer.Token) *ParseError {
+	switch tok.Type {
+	case lexer.TokenInvalid:
+		return p.fail(tok, "%s", tok.Err.Msg)
+	case lexer.TokenEOF:
+		return p.fail(tok, "unexpected end of input")
+	}
+	return p.fail(tok, "unexpected %s in state %s", describe(tok), p.top().state)
+}
 
-		switch tok.Type {
-		case lexer.TokenSquareClose:
-			if state == stateArrayValueOrEnd && justSawComma {
-				fmt.Println("DEBUG: Trailing comma before ']' is not allowed")
-				return false
-			}
-			if state != stateArrayValueOrEnd && state != stateArrayCommaOrEnd {
-				fmt.Println("DEBUG: Unexpected ']' in state", state)
-				return false
-			}
-			return true
+// describe names tok for error messages.
+func describe(tok lexer.Token) string {
+	if tok.Type.IsStructural() {
+		return fmt.Sprintf("'%s'", tok.Type)
+	}
+	return tok.Type.String()
+}
 
-		case lexer.TokenComma:
-			if state != stateArrayCommaOrEnd {
-				fmt.Printf("DEBUG: Unexpected comma in state %s\n", state)
-				return false
-			}
-			state = stateArrayValueOrEnd
-			justSawComma = true
+// Parse reads a JSON value and reports whether it is valid. The returned
+// error is a *ParseError, or an ErrorList if the parser was configured with
+// Recover.
+func (p *Parser) Parse() error {
+	return p.parse()
+}
 
-		case lexer.TokenCurlyOpen:
-			if state != stateArrayValueOrEnd {
-				fmt.Printf("DEBUG: Unexpected '{' in state %s\n", state)
-				return false
-			}
-			if !p.parseObject() {
-				return false
-			}
-			state = stateArrayCommaOrEnd
+// ParseValue reads a JSON value like Parse and returns it as a document
+// tree.
+func (p *Parser) ParseValue() (Value, error) {
+	tree := &treeBuilder{p: p, duplicates: p.duplicates}
+	p.handler = tree
+	defer func() { p.handler = nil }()
 
-		case lexer.TokenSquareOpen:
-			if state != stateArrayValueOrEnd {
-				fmt.Printf("DEBUG: Unexpected '[' in state %s\n", state)
-				return false
-			}
-			if !p.parseArray() {
-				return false
-			}
-			state = stateArrayCommaOrEnd
+	if err := p.parse(); err != nil {
+		if p.recovery {
+			return tree.root, err
+		}
+		return nil, err
+	}
+	return tree.root, nil
+}
 
-		case lexer.TokenString, lexer.TokenNumber, lexer.TokenBool, lexer.TokenNull:
-			if state != stateArrayValueOrEnd {
-				fmt.Printf("DEBUG: Unexpected value in state %s\n", state)
-				return false
-			}
-			state = stateArrayCommaOrEnd
+// frame is an object or array being parsed, or the top level.
+type frame struct {
+	state      parserState
+	afterComma bool                      // the last token was a comma
+	comma      lexer.Position            // where that comma is
+	keys       map[string]lexer.Position // keys seen, unless DuplicateKeepAll
+}
 
-		case lexer.TokenInvalid:
-			fmt.Println("DEBUG: Invalid token encountered")
-			return false
+func (p *Parser) top() *frame {
+	return &p.stack[len(p.stack)-1]
+}
 
-		case lexer.TokenEOF:
-			fmt.Println("DEBUG: Unexpected end of input")
-			return false
+// isObject reports whether f is an object rather than an array or the top
+// level.
+func (f *frame) isObject() bool {
+	switch f.state {
+	case stateExpectKeyOrEnd, stateExpectColon, stateExpectValue, stateExpectCommaOrEnd:
+		return true
+	}
+	return false
+}
 
-		default:
-			fmt.Printf("DEBUG: Unknown token type: %s\n", tok.Type)
-			return false
+// parse runs the state machine over the whole input. Nested objects and
+// arrays are tracked on p.stack rather than by recursion, so the nesting
+// depth is bounded by MaxDepth instead of the goroutine stack.
+func (p *Parser) parse() error {
+	p.reset()
+	for {
+		done, err := p.step()
+		if err != nil && p.recovery {
+			done, err = p.recover(err)
+		} else if err != nil {
+			p.report(err)
+		}
+		if err != nil {
+			return err
+		}
+		if done {
+			if len(p.errs) > 0 {
+				return p.errs
+			}
+			return nil
 		}
 	}
 }
 
-// parseObject parses a JSON object and any nested objects recursively.
-func (p *Parser) parseObject() bool {
-	state := stateExpectKeyOrEnd
-	justSawComma := false
+// reset prepares the parser to read a new top-level value.
+func (p *Parser) reset() {
+	p.stack = append(p.stack[:0], frame{state: stateStart})
+	p.diagnostics = nil
+	p.errs = nil
+}
+
+// step reads one token and handles it. It reports done once the input has
+// ended after a complete value.
+func (p *Parser) step() (done bool, err error) {
+	tok := p.next()
+	p.trace(p.top().state, tok)
+	p.tok = tok
+	return p.handle(tok)
+}
 
+// next reads the next token, passing over the comments of a lexer that
+// keeps them.
+func (p *Parser) next() lexer.Token {
 	for {
 		tok := p.lexer.NextToken()
-		fmt.Printf("DEBUG: State = %-20s | Token = %-10s | Literal = %s\n", state, tok.Type, tok.Literal)
+		if tok.Type != lexer.TokenComment {
+			return tok
+		}
+		p.trace(p.top().state, tok)
+	}
+}
+
+// handle runs the state machine on tok.
+func (p *Parser) handle(tok lexer.Token) (done bool, err error) {
+	f := p.top()
+	if tok.Type == lexer.TokenInvalid {
+		return false, p.unexpected(tok)
+	}
+
+	switch f.state {
+	case stateStart, stateExpectValue, stateArrayValueOrEnd:
+		if err := p.parseValue(tok); err != nil {
+			return false, err
+		}
 
+	case stateExpectKeyOrEnd:
 		switch tok.Type {
+		case lexer.TokenString, lexer.TokenIdentifier:
+			if err := p.key(tok); err != nil {
+				return false, err
+			}
+			f.state, f.afterComma = stateExpectColon, false
 		case lexer.TokenCurlyClose:
-			if state == stateExpectKeyOrEnd && justSawComma {
-				fmt.Println("DEBUG: Trailing comma before '}' is not allowed")
-				return false
+			if f.afterComma {
+				if err := p.trailingComma(tok); err != nil {
+					return false, err
+				}
 			}
-			if state != stateExpectKeyOrEnd && state != stateExpectCommaOrEnd {
-				fmt.Println("DEBUG: Unexpected '}' in state", state)
-				return false
+			if err := p.pop(tok); err != nil {
+				return false, err
 			}
-			return true
-
-		case lexer.TokenColon:
-			if state != stateExpectColon {
-				fmt.Println("DEBUG: Unexpected ':' — expected key first")
-				return false
+		case lexer.TokenNumber, lexer.TokenBool, lexer.TokenNull:
+			if !p.keyword(tok) {
+				return false, p.fail(tok, "object key must be STRING but got %s", tok.Type)
 			}
-			state = stateExpectValue
+			if err := p.key(tok); err != nil {
+				return false, err
+			}
+			f.state, f.afterComma = stateExpectColon, false
+		case lexer.TokenColon:
+			return false, p.fail(tok, "unexpected ':' — expected key first")
+		default:
+			return false, p.unexpected(tok)
+		}
 
+	case stateExpectColon:
+		switch tok.Type {
+		case lexer.TokenColon:
+			f.state = stateExpectValue
 		case lexer.TokenString, lexer.TokenNumber, lexer.TokenBool, lexer.TokenNull:
-			switch state {
-			case stateExpectKeyOrEnd:
-				if tok.Type != lexer.TokenString {
-					fmt.Printf("DEBUG: Object key must be STRING but got %s\n", tok.Type)
-					return false
-				}
-				state = stateExpectColon
-
-			case stateExpectValue:
-				state = stateExpectCommaOrEnd
+			return false, p.fail(tok, "missing ':' after object key")
+		default:
+			return false, p.unexpected(tok)
+		}
 
-			default:
-				fmt.Printf("DEBUG: Unexpected value in state %s\n", state)
-				return false
+	case stateExpectCommaOrEnd:
+		switch tok.Type {
+		case lexer.TokenComma:
+			f.state, f.afterComma, f.comma = stateExpectKeyOrEnd, true, tok.Pos
+		case lexer.TokenCurlyClose:
+			if err := p.pop(tok); err != nil {
+				return false, err
 			}
+		case lexer.TokenString, lexer.TokenNumber, lexer.TokenBool, lexer.TokenNull:
+			return false, p.fail(tok, "missing ',' before %s", describe(tok))
+		case lexer.TokenColon:
+			return false, p.fail(tok, "unexpected ':' — expected key first")
+		default:
+			return false, p.unexpected(tok)
+		}
 
-		case lexer.TokenCurlyOpen:
-			if state != stateExpectValue {
-				fmt.Printf("DEBUG: Unexpected '{' in state %s\n", state)
-				return false
-			}
-			if !p.parseObject() {
-				return false
+	case stateArrayCommaOrEnd:
+		switch tok.Type {
+		case lexer.TokenComma:
+			f.state, f.afterComma, f.comma = stateArrayValueOrEnd, true, tok.Pos
+		case lexer.TokenSquareClose:
+			if err := p.pop(tok); err != nil {
+				return false, err
 			}
-			state = stateExpectCommaOrEnd
+		case lexer.TokenCurlyOpen, lexer.TokenSquareOpen,
+			lexer.TokenString, lexer.TokenNumber, lexer.TokenBool, lexer.TokenNull:
+			return false, p.fail(tok, "missing ',' before %s", describe(tok))
+		default:
+			return false, p.unexpected(tok)
+		}
 
-		case lexer.TokenComma:
-			if state != stateExpectCommaOrEnd {
-				fmt.Printf("DEBUG: Unexpected comma in state %s\n", state)
-				return false
+	case stateDone:
+		if tok.Type != lexer.TokenEOF {
+			return false, p.fail(tok, "extra %s after end of value", describe(tok))
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// parseValue handles tok in a state that expects a value: it opens a
+// nested object or array or consumes a scalar. In an array, ']' may close
+// it instead.
+func (p *Parser) parseValue(tok lexer.Token) error {
+	f := p.top()
+	switch tok.Type {
+	case lexer.TokenCurlyOpen, lexer.TokenSquareOpen:
+		if p.maxDepth > 0 && len(p.stack) > p.maxDepth {
+			err := p.fail(tok, "nesting too deep: more than %d levels", p.maxDepth)
+			err.Err = ErrTooDeep
+			return err
+		}
+		if err := p.open(tok); err != nil {
+			return err
+		}
+		f.afterComma = false
+		if tok.Type == lexer.TokenCurlyOpen {
+			p.stack = append(p.stack, frame{state: stateExpectKeyOrEnd})
+		} else {
+			p.stack = append(p.stack, frame{state: stateArrayValueOrEnd})
+		}
+
+	case lexer.TokenString, lexer.TokenNumber, lexer.TokenBool, lexer.TokenNull:
+		if f.state == stateStart && p.containerRoot {
+			return p.fail(tok, "JSON must start with '{' or '['")
+		}
+		if err := p.scalar(tok); err != nil {
+			return err
+		}
+		p.valueDone()
+
+	case lexer.TokenSquareClose:
+		if f.state != stateArrayValueOrEnd {
+			return p.unexpected(tok)
+		}
+		if f.afterComma {
+			if err := p.trailingComma(tok); err != nil {
+				return err
 			}
-			state = stateExpectKeyOrEnd
-			justSawComma = true
+		}
+		return p.pop(tok)
 
-		case lexer.TokenSquareOpen:
-			if state != stateExpectValue {
-				fmt.Printf("DEBUG: Unexpected '[' in state %s\n", state)
-				return false
+	case lexer.TokenColon:
+		if f.state == stateExpectValue {
+			return p.fail(tok, "unexpected ':' — expected key first")
+		}
+		return p.unexpected(tok)
+
+	case lexer.TokenIdentifier:
+		return p.fail(tok, "unexpected identifier '%s': only object keys may be unquoted", p.lexer.Text(tok))
+
+	default:
+		return p.unexpected(tok)
+	}
+	return nil
+}
+
+// pop closes the innermost object or array with tok.
+func (p *Parser) pop(tok lexer.Token) error {
+	if err := p.close(tok); err != nil {
+		return err
+	}
+	p.stack = p.stack[:len(p.stack)-1]
+	p.valueDone()
+	return nil
+}
+
+// valueDone moves the innermost frame past the value it just consumed.
+func (p *Parser) valueDone() {
+	f := p.top()
+	f.afterComma = false
+	switch f.state {
+	case stateStart:
+		f.state = stateDone
+	case stateExpectValue:
+		f.state = stateExpectCommaOrEnd
+	case stateArrayValueOrEnd:
+		f.state = stateArrayCommaOrEnd
+	}
+}
+
+// open reports the object or array tok opens to the handler.
+func (p *Parser) open(tok lexer.Token) error {
+	if p.handler == nil {
+		return nil
+	}
+	if tok.Type == lexer.TokenCurlyOpen {
+		return p.handled(tok, p.handler.StartObject(tok.Pos))
+	}
+	return p.handled(tok, p.handler.StartArray(tok.Pos))
+}
+
+// close reports the end of the innermost object or array, at tok, to the
+// handler.
+func (p *Parser) close(tok lexer.Token) error {
+	if p.handler == nil {
+		return nil
+	}
+	if p.top().isObject() {
+		return p.handled(tok, p.handler.EndObject(tok.Pos))
+	}
+	return p.handled(tok, p.handler.EndArray(tok.Pos))
+}
+
+// key checks the object key tok against the keys seen before in the same
+// object and reports it to the handler.
+func (p *Parser) key(tok lexer.Token) error {
+	if p.handler == nil && p.duplicates == DuplicateKeepAll && p.surrogates != lexer.SurrogateError {
+		return nil
+	}
+	key, err := p.unquote(tok)
+	if err != nil {
+		return p.fail(tok, "invalid object key: %v", err)
+	}
+
+	dup := false
+	if p.duplicates != DuplicateKeepAll {
+		f := p.top()
+		if first, ok := f.keys[key]; ok {
+			dup = true
+			switch p.duplicates {
+			case DuplicateReject:
+				err := p.fail(tok, "duplicate key %q, first defined at %s", key, first)
+				err.Related = &first
+				err.Err = ErrDuplicateKey
+				return err
+			case DuplicateWarn:
+				p.warn(tok.Pos, &first, "duplicate key %q, first defined at %s", key, first)
 			}
-			if !p.parseArray() {
-				return false
+		} else {
+			if f.keys == nil {
+				f.keys = make(map[string]lexer.Position)
 			}
-			state = stateExpectCommaOrEnd
+			f.keys[key] = tok.Pos
+		}
+	}
 
-		case lexer.TokenInvalid:
-			fmt.Println("DEBUG: Invalid token encountered")
-			return false
+	if p.handler == nil {
+		return nil
+	}
+	p.dupKey = dup
+	return p.handled(tok, p.handler.Key(key, tok.Pos))
+}
 
-		case lexer.TokenEOF:
-			fmt.Println("DEBUG: Unexpected end of input")
-			return false
+// trailingComma applies the trailing comma policy to the comma before the
+// '}' or ']' tok.
+func (p *Parser) trailingComma(tok lexer.Token) error {
+	switch p.commas {
+	case TrailingCommaReject:
+		return p.fail(tok, "trailing comma before '%s' is not allowed", tok.Type)
+	case TrailingCommaWarn:
+		p.warn(p.top().comma, nil, "trailing comma before '%s'", tok.Type)
+	}
+	return nil
+}
 
-		default:
-			fmt.Printf("DEBUG: Unknown token type: %s\n", tok.Type)
-			return false
+// warn records a diagnostic at pos.
+func (p *Parser) warn(pos lexer.Position, related *lexer.Position, format string, args ...any) {
+	p.diagnostics = append(p.diagnostics, Diagnostic{Pos: pos, Related: related, Msg: fmt.Sprintf(format, args...)})
+}
+
+// Diagnostics returns the warnings recorded by the last parse, such as
+// duplicate keys under DuplicateWarn.
+func (p *Parser) Diagnostics() []Diagnostic {
+	return p.diagnostics
+}
+
+// keyword reports whether tok is a JSON5 object key spelled like a
+// literal, such as {null: 1} or {Infinity: 2}.
+func (p *Parser) keyword(tok lexer.Token) bool {
+	if !p.json5 {
+		return false
+	}
+	if tok.Type == lexer.TokenNumber {
+		text := p.lexer.Text(tok)
+		return text == "Infinity" || text == "NaN"
+	}
+	return true
+}
+
+// unquote decodes the text of a STRING token, or of a JSON5 identifier.
+func (p *Parser) unquote(tok lexer.Token) (string, error) {
+	if p.json5 {
+		return lexer.UnquoteJSON5(p.lexer.Text(tok), p.surrogates)
+	}
+	return lexer.Unquote(p.lexer.Text(tok), p.surrogates)
+}
+
+// scalar reports the value of tok to the handler. Without one, strings
+// are still decoded under lexer.SurrogateError, so that Parse rejects the
+// lone surrogates ParseValue does.
+func (p *Parser) scalar(tok lexer.Token) error {
+	if p.handler == nil {
+		if tok.Type == lexer.TokenString && p.surrogates == lexer.SurrogateError {
+			if _, err := p.unquote(tok); err != nil {
+				return p.fail(tok, "invalid string: %v", err)
+			}
+		}
+		return nil
+	}
+
+	var err error
+	switch tok.Type {
+	case lexer.TokenString:
+		str, uerr := p.unquote(tok)
+		if uerr != nil {
+			return p.fail(tok, "invalid string: %v", uerr)
 		}
+		err = p.handler.String(str, tok.Pos)
+	case lexer.TokenNumber:
+		err = p.handler.Number(p.lexer.Text(tok), tok.Number, tok.Pos)
+	case lexer.TokenBool:
+		err = p.handler.Bool(tok.Literal == "true", tok.Pos)
+	case lexer.TokenNull:
+		err = p.handler.Null(tok.Pos)
 	}
+	return p.handled(tok, err)
 }
@@ -1,6 +1,9 @@
 package parser
 
 import (
+	"errors"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/HrithikSawant/go-json-parser/lexer"
@@ -10,7 +13,7 @@ func runParserTest(t *testing.T, name string, input string, expectValid bool) {
 	t.Run(name, func(t *testing.T) {
 		lex := lexer.NewLexer(input)
 		p := NewParser(lex)
-		valid := p.Parse()
+		valid := p.Parse() == nil
 
 		if valid != expectValid {
 			status := "valid"
@@ -28,7 +31,7 @@ func TestStep1(t *testing.T) {
 	runParserTest(t, "OnlyClosingBrace", `}`, false)
 	runParserTest(t, "ExtraComma", `{,}`, false)
 	runParserTest(t, "TrailingCharacters", `{} extra`, false)
-	runParserTest(t, "JustString", `"key"`, false)
+	runParserTest(t, "JustString", `"key"`, true)
 	runParserTest(t, "EmptyInput", ``, false)
 }
 
@@ -158,3 +161,337 @@ func TestStep4_EmptyArray(t *testing.T) {
 func TestStep4_ArrayTrailingComma(t *testing.T) {
 	runParserTest(t, "ArrayTrailingComma", `{"bad": [1, 2,]}`, false)
 }
+
+func TestStep5_StringEscapes(t *testing.T) {
+	runParserTest(t, "ValidEscapes", `["\"\\\/\b\f\n\r\tA"]`, true)
+	runParserTest(t, "IllegalBackslashEscape", `["Illegal backslash escape: \x15"]`, false)
+	runParserTest(t, "IllegalOctalEscape", `["Illegal backslash escape: \017"]`, false)
+	runParserTest(t, "TabCharacterInString", "[\"\ttab\tcharacter\tin\tstring\t\"]", false)
+	runParserTest(t, "LineBreakInString", "[\"line\nbreak\"]", false)
+	runParserTest(t, "EscapedLineBreak", "[\"line\\\nbreak\"]", false)
+	runParserTest(t, "ShortUnicodeEscape", `["\u12"]`, false)
+}
+
+func TestStep5_Numbers(t *testing.T) {
+	runParserTest(t, "NumberGrammar", `[0, -0, 1.5, -1.5e+10, 2E-3, 123456789]`, true)
+	runParserTest(t, "LeadingZeros", `{"a": 013}`, false)
+	runParserTest(t, "LoneMinus", `[-]`, false)
+	runParserTest(t, "MissingFractionDigits", `[1.]`, false)
+	runParserTest(t, "LeadingDecimalPoint", `[-.5]`, false)
+	runParserTest(t, "HexNumber", `[0x14]`, false)
+	runParserTest(t, "EmptyExponent", `[0e]`, false)
+	runParserTest(t, "ExponentSignOnly", `[0e+]`, false)
+}
+
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		line     int
+		column   int
+		state    string
+		found    lexer.TokenType
+		expected []lexer.TokenType
+		message  string
+	}{
+		{"MissingColon", "{\n  \"key\" \"value\"\n}", 2, 9, "ExpectColon", lexer.TokenString,
+			[]lexer.TokenType{lexer.TokenColon}, "missing ':' after object key"},
+		{"TrailingComma", `{"a": 1,}`, 1, 9, "ExpectKeyOrEnd", lexer.TokenCurlyClose,
+			[]lexer.TokenType{lexer.TokenString}, "trailing comma before '}' is not allowed"},
+		{"MissingComma", `[1 2]`, 1, 4, "ArrayCommaOrEnd", lexer.TokenNumber,
+			[]lexer.TokenType{lexer.TokenComma, lexer.TokenSquareClose}, "missing ',' before NUMBER"},
+		{"UnexpectedEnd", `{"a": [`, 1, 8, "ArrayValueOrEnd", lexer.TokenEOF,
+			[]lexer.TokenType{lexer.TokenCurlyOpen, lexer.TokenSquareOpen, lexer.TokenString, lexer.TokenNumber, lexer.TokenBool, lexer.TokenNull, lexer.TokenSquareClose}, "unexpected end of input"},
+		{"ExtraTokens", `{} []`, 1, 4, "Done", lexer.TokenSquareOpen,
+			[]lexer.TokenType{lexer.TokenEOF}, "extra '[' after end of value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewParser(lexer.NewLexer(tt.input)).Parse()
+
+			var perr *ParseError
+			if !errors.As(err, &perr) {
+				t.Fatalf("Expected a *ParseError, got %v", err)
+			}
+			if perr.Pos.Line != tt.line || perr.Pos.Column != tt.column {
+				t.Errorf("got position %s, expected line %d, column %d", perr.Pos, tt.line, tt.column)
+			}
+			if perr.State != tt.state || perr.Found.Type != tt.found || perr.Msg != tt.message {
+				t.Errorf("got (%s, %s, %q), expected (%s, %s, %q)", perr.State, perr.Found.Type, perr.Msg, tt.state, tt.found, tt.message)
+			}
+			if !slices.Equal(perr.Expected, tt.expected) {
+				t.Errorf("got expected tokens %v, expected %v", perr.Expected, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseError_WrapsLexerError(t *testing.T) {
+	err := NewParser(lexer.NewLexer(`{"a": "\x"}`)).Parse()
+
+	var lexErr *lexer.Error
+	if !errors.As(err, &lexErr) {
+		t.Fatalf("Expected the error to wrap a *lexer.Error, got %v", err)
+	}
+	if lexErr.Reason != lexer.ReasonBadEscape {
+		t.Errorf("Expected reason %s, got %s", lexer.ReasonBadEscape, lexErr.Reason)
+	}
+	if msg := err.Error(); msg != `line 1, column 8: invalid escape sequence '\x' in string` {
+		t.Errorf("Unexpected message %q", msg)
+	}
+}
+
+type recordingTracer struct {
+	events []TraceEvent
+}
+
+func (r *recordingTracer) Trace(ev TraceEvent) {
+	r.events = append(r.events, ev)
+}
+
+func TestTracer(t *testing.T) {
+	tracer := &recordingTracer{}
+	p := NewParser(lexer.NewLexer(`{"a": [1]}`), WithTracer(tracer))
+	if err := p.Parse(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []struct {
+		state string
+		typ   lexer.TokenType
+		depth int
+	}{
+		{"Start", lexer.TokenCurlyOpen, 0},
+		{"ExpectKeyOrEnd", lexer.TokenString, 1},
+		{"ExpectColon", lexer.TokenColon, 1},
+		{"ExpectValue", lexer.TokenSquareOpen, 1},
+		{"ArrayValueOrEnd", lexer.TokenNumber, 2},
+		{"ArrayCommaOrEnd", lexer.TokenSquareClose, 2},
+		{"ExpectCommaOrEnd", lexer.TokenCurlyClose, 1},
+		{"Done", lexer.TokenEOF, 0},
+	}
+	if len(tracer.events) != len(expected) {
+		t.Fatalf("Expected %d events, got %d", len(expected), len(tracer.events))
+	}
+	for i, exp := range expected {
+		ev := tracer.events[i]
+		if ev.State != exp.state || ev.Token.Type != exp.typ || ev.Depth != exp.depth {
+			t.Errorf("Event %d - got (%s, %s, %d), expected (%s, %s, %d)", i, ev.State, ev.Token.Type, ev.Depth, exp.state, exp.typ, exp.depth)
+		}
+	}
+}
+
+func TestTableTracer(t *testing.T) {
+	var out strings.Builder
+	p := NewParser(lexer.NewLexer(`{}`), WithTracer(NewTableTracer(&out)))
+	if err := p.Parse(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "DEBUG: State = Start                | Token = {          | Literal = {\n" +
+		"DEBUG: State = ExpectKeyOrEnd       | Token = }          | Literal = }\n" +
+		"DEBUG: State = Done                 | Token = EOF        | Literal = \n"
+	if out.String() != expected {
+		t.Errorf("got\n%s\nexpected\n%s", out.String(), expected)
+	}
+}
+
+func TestStep5_TopLevelScalars(t *testing.T) {
+	runParserTest(t, "Number", `42`, true)
+	runParserTest(t, "NegativeFloat", ` -1.5e3 `, true)
+	runParserTest(t, "String", `"hello"`, true)
+	runParserTest(t, "True", `true`, true)
+	runParserTest(t, "Null", `null`, true)
+	runParserTest(t, "TwoScalars", `1 2`, false)
+	runParserTest(t, "ScalarThenObject", `"a" {}`, false)
+	runParserTest(t, "BareWord", `hello`, false)
+}
+
+func TestRequireObjectOrArray(t *testing.T) {
+	for _, input := range []string{`42`, `"hello"`, `null`} {
+		err := NewParser(lexer.NewLexer(input), RequireObjectOrArray()).Parse()
+
+		var perr *ParseError
+		if !errors.As(err, &perr) || perr.Msg != "JSON must start with '{' or '['" {
+			t.Errorf("%s - expected the RFC 4627 error, got %v", input, err)
+			continue
+		}
+		if !slices.Equal(perr.Expected, []lexer.TokenType{lexer.TokenCurlyOpen, lexer.TokenSquareOpen}) {
+			t.Errorf("%s - got expected tokens %v", input, perr.Expected)
+		}
+	}
+
+	if err := NewParser(lexer.NewLexer(`[42]`), RequireObjectOrArray()).Parse(); err != nil {
+		t.Errorf("Expected an array to be accepted, got %v", err)
+	}
+}
+
+func TestMaxDepth(t *testing.T) {
+	runParserTest(t, "DefaultAllowsModerateNesting", strings.Repeat("[", 500)+strings.Repeat("]", 500), true)
+
+	err := NewParser(lexer.NewLexer(`[[{"a": [1]}]]`), MaxDepth(3)).Parse()
+	var perr *ParseError
+	if !errors.As(err, &perr) || !errors.Is(err, ErrTooDeep) {
+		t.Fatalf("Expected a ParseError wrapping ErrTooDeep, got %v", err)
+	}
+	if perr.Pos.Column != 9 || perr.Msg != "nesting too deep: more than 3 levels" {
+		t.Errorf("got (%s, %q)", perr.Pos, perr.Msg)
+	}
+
+	if err := NewParser(lexer.NewLexer(`[[{"a": 1}]]`), MaxDepth(3)).Parse(); err != nil {
+		t.Errorf("Expected nesting of exactly 3 levels to pass, got %v", err)
+	}
+}
+
+func TestDeepNestingDoesNotRecurse(t *testing.T) {
+	const depth = 1_000_000
+	input := strings.Repeat("[", depth) + strings.Repeat("]", depth)
+
+	if err := NewParser(lexer.NewLexer(input), MaxDepth(0)).Parse(); err != nil {
+		t.Errorf("Expected %d nested arrays to parse without a limit, got %v", depth, err)
+	}
+	if err := NewParser(lexer.NewLexer(input)).Parse(); !errors.Is(err, ErrTooDeep) {
+		t.Errorf("Expected the default limit to reject %d nested arrays, got %v", depth, err)
+	}
+	if err := NewParser(lexer.NewLexer(strings.Repeat("[", depth))).Parse(); err == nil {
+		t.Errorf("Expected unterminated nesting to fail")
+	}
+}
+
+func TestDuplicateKeys_Reject(t *testing.T) {
+	input := "{\n  \"a\": 1,\n  \"b\": {\"a\": 2},\n  \"\\u0061\": 3\n}"
+	err := NewParser(lexer.NewLexer(input), WithDuplicateKeys(DuplicateReject)).Parse()
+
+	var perr *ParseError
+	if !errors.As(err, &perr) || !errors.Is(err, ErrDuplicateKey) {
+		t.Fatalf("Expected a ParseError wrapping ErrDuplicateKey, got %v", err)
+	}
+	if perr.Pos.Line != 4 || perr.Related == nil || perr.Related.Line != 2 {
+		t.Errorf("Expected the error at line 4 to refer to line 2, got %s and %v", perr.Pos, perr.Related)
+	}
+	if msg := err.Error(); msg != `line 4, column 3: duplicate key "a", first defined at line 2, column 3` {
+		t.Errorf("Unexpected message %q", msg)
+	}
+
+	runParserTest(t, "SameKeyInSiblingObjects", `[{"a": 1}, {"a": 2}]`, true)
+	runParserTest(t, "DuplicatesAllowedByDefault", `{"a": 1, "a": 2}`, true)
+}
+
+func TestDuplicateKeys_Warn(t *testing.T) {
+	p := NewParser(lexer.NewLexer(`{"a": 1, "b": 2, "a": 3, "a": 4}`), WithDuplicateKeys(DuplicateWarn))
+	if err := p.Parse(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	diags := p.Diagnostics()
+	if len(diags) != 2 {
+		t.Fatalf("Expected 2 diagnostics, got %v", diags)
+	}
+	for i, column := range []int{18, 26} {
+		if diags[i].Pos.Column != column || diags[i].Related == nil || diags[i].Related.Column != 2 {
+			t.Errorf("Diagnostic %d - got %s related to %v", i, diags[i], diags[i].Related)
+		}
+	}
+}
+
+func TestJSON5(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		valid bool
+	}{
+		{"UnquotedKeys", `{name: 'x', $id: 1, _: 2}`, true},
+		{"KeywordKeys", `{true: 1, null: 2, Infinity: 3}`, true},
+		{"TrailingCommas", `{a: [1, 2,],}`, true},
+		{"Comments", "// header\n{/* a */ a: 1 // b\n}", true},
+		{"Numbers", `[0x1F, .5, 5., +1, -Infinity, NaN]`, true},
+		{"UnquotedValue", `{a: b}`, false},
+		{"NumericKey", `{1: 2}`, false},
+		{"OnlyComma", `[,]`, false},
+		{"DoubleTrailingComma", `[1,,]`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lex := lexer.NewLexer(tt.input, lexer.WithDialect(lexer.DialectJSON5))
+			if err := NewParser(lex).Parse(); (err == nil) != tt.valid {
+				t.Errorf("Parse(%q) = %v, expected valid = %v", tt.input, err, tt.valid)
+			}
+		})
+	}
+
+	// The same input stays invalid JSON.
+	runParserTest(t, "JSON5InJSON", `{a: 1,}`, false)
+}
+
+func TestJSONC(t *testing.T) {
+	input := `{
+		// Compiler settings
+		"compilerOptions": {
+			"strict": true, /* for now */
+			"paths": ["src",],
+		},
+	}`
+
+	for _, keep := range []bool{false, true} {
+		opts := []lexer.Option{lexer.WithDialect(lexer.DialectJSONC)}
+		if keep {
+			opts = append(opts, lexer.KeepComments())
+		}
+		if err := NewParser(lexer.NewLexer(input, opts...)).Parse(); err == nil || !strings.Contains(err.Error(), "trailing comma") {
+			t.Errorf("Expected trailing commas to be rejected by default, got %v", err)
+		}
+		if err := NewParser(lexer.NewLexer(input, opts...), WithTrailingCommas(TrailingCommaAllow)).Parse(); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	}
+
+	runParserTest(t, "CommentInJSON", `[1 /* no */]`, false)
+	runParserTest(t, "TrailingCommaInJSON", `[1,]`, false)
+}
+
+func TestTrailingCommas(t *testing.T) {
+	input := "{\n  \"a\": [1, 2,],\n  \"b\": {},\n}"
+
+	err := NewParser(lexer.NewLexer(input), WithTrailingCommas(TrailingCommaReject)).Parse()
+	if err == nil || err.Error() != "line 2, column 14: trailing comma before ']' is not allowed" {
+		t.Errorf("Unexpected error %v", err)
+	}
+
+	if err := NewParser(lexer.NewLexer(input), WithTrailingCommas(TrailingCommaAllow)).Parse(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	p := NewParser(lexer.NewLexer(input), WithTrailingCommas(TrailingCommaWarn))
+	if err := p.Parse(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var got []string
+	for _, d := range p.Diagnostics() {
+		got = append(got, d.String())
+	}
+	expected := []string{
+		"line 2, column 13: trailing comma before ']'",
+		"line 3, column 10: trailing comma before '}'",
+	}
+	if !slices.Equal(got, expected) {
+		t.Errorf("Got diagnostics %q, expected %q", got, expected)
+	}
+
+	// Only one comma may trail, and not in an empty container.
+	for _, input := range []string{`[1,,]`, `[,]`, `{,}`} {
+		if err := NewParser(lexer.NewLexer(input), WithTrailingCommas(TrailingCommaAllow)).Parse(); err == nil {
+			t.Errorf("Expected %s to be invalid", input)
+		}
+	}
+
+	// JSON5 allows them unless told otherwise.
+	json5 := func() *lexer.Lexer { return lexer.NewLexer(`[1,]`, lexer.WithDialect(lexer.DialectJSON5)) }
+	if err := NewParser(json5()).Parse(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := NewParser(json5(), WithTrailingCommas(TrailingCommaReject)).Parse(); err == nil {
+		t.Error("Expected the trailing comma to be rejected")
+	}
+}
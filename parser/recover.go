@@ -0,0 +1,125 @@
+package parser
+
+import (
+	"errors"
+
+	"github.com/HrithikSawant/go-json-parser/lexer"
+)
+
+// recover records the syntax error err, found at p.tok, and moves the
+// parser to a point where it can go on. It returns the error to stop with
+// instead if err cannot be recovered from or the error limit is reached.
+func (p *Parser) recover(err error) (done bool, _ error) {
+	var perr *ParseError
+	if !errors.As(err, &perr) || errors.Is(err, ErrStop) {
+		return false, err
+	}
+	p.errs = append(p.errs, perr)
+	p.report(perr)
+	if !recoverable(perr) || p.maxErrors > 0 && len(p.errs) >= p.maxErrors {
+		return false, p.errs
+	}
+
+	tok := p.tok
+	f := p.top()
+	if tok.Type.IsValue() || tok.Type == lexer.TokenCurlyOpen || tok.Type == lexer.TokenSquareOpen {
+		// Assume the missing ',' or ':' and read tok again.
+		retry := true
+		switch f.state {
+		case stateExpectCommaOrEnd:
+			f.state, f.afterComma = stateExpectKeyOrEnd, true
+		case stateArrayCommaOrEnd:
+			f.state, f.afterComma = stateArrayValueOrEnd, true
+		case stateExpectColon:
+			f.state = stateExpectValue
+		default:
+			retry = false
+		}
+		if retry {
+			done, err := p.handle(tok)
+			if err != nil {
+				return p.recover(err)
+			}
+			return done, nil
+		}
+	}
+	return p.resync(tok)
+}
+
+// recoverable reports whether the parser can go on after err: it can after
+// a syntax error, but not after a read error or an error from a Handler.
+func recoverable(err *ParseError) bool {
+	if fromHandler(err) {
+		return false
+	}
+	var lexErr *lexer.Error
+	return !errors.As(err.Err, &lexErr) || lexErr.Reason != lexer.ReasonIO
+}
+
+// resync skips from the bad token tok to the next ',', '}' or ']' that
+// belongs to an open object or array, stepping over nested ones, and
+// continues from there. At the end of the input it closes every open
+// object and array.
+func (p *Parser) resync(tok lexer.Token) (done bool, err error) {
+	depth := 0
+	for skipped := false; ; skipped = true {
+		switch tok.Type {
+		case lexer.TokenCurlyOpen, lexer.TokenSquareOpen:
+			depth++
+		case lexer.TokenCurlyClose, lexer.TokenSquareClose:
+			if depth > 0 {
+				depth--
+			} else if p.closes(tok) {
+				return false, p.closeTo(tok)
+			}
+		case lexer.TokenComma:
+			if depth == 0 && len(p.stack) > 1 {
+				f := p.top()
+				if f.isObject() {
+					f.state = stateExpectKeyOrEnd
+				} else {
+					f.state = stateArrayValueOrEnd
+				}
+				f.afterComma, f.comma = true, tok.Pos
+				return false, nil
+			}
+		case lexer.TokenEOF:
+			if skipped && len(p.stack) > 1 {
+				perr := p.unexpected(tok)
+				p.errs = append(p.errs, perr)
+				p.report(perr)
+			}
+			for len(p.stack) > 1 {
+				if err := p.pop(tok); err != nil {
+					return false, err
+				}
+			}
+			return true, nil
+		}
+
+		tok = p.next()
+		p.trace(p.top().state, tok)
+		p.tok = tok
+	}
+}
+
+// closes reports whether an open object or array can be closed by tok.
+func (p *Parser) closes(tok lexer.Token) bool {
+	for i := len(p.stack) - 1; i > 0; i-- {
+		if p.stack[i].isObject() == (tok.Type == lexer.TokenCurlyClose) {
+			return true
+		}
+	}
+	return false
+}
+
+// closeTo closes the objects and arrays up to the innermost one tok can
+// close, and that one.
+func (p *Parser) closeTo(tok lexer.Token) error {
+	for {
+		match := p.top().isObject() == (tok.Type == lexer.TokenCurlyClose)
+		if err := p.pop(tok); err != nil || match {
+			return err
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package parser
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/HrithikSawant/go-json-parser/lexer"
+)
+
+func TestRecover_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		messages []string
+		tree     string // the keys or elements left in the tree
+	}{
+		{
+			"MissingCommas",
+			`[1 2, 3,]`,
+			[]string{"missing ',' before NUMBER", "trailing comma before ']' is not allowed"},
+			"[1 2 3]",
+		},
+		{
+			"BadValues",
+			`{"a": 1, "b": tru, "c": [1, 2}`,
+			[]string{"unknown literal 'tru'", "unexpected '}' in state ArrayCommaOrEnd"},
+			"{a c}",
+		},
+		{
+			"MissingColon",
+			`{"a" 1, "b": 2}`,
+			[]string{"missing ':' after object key"},
+			"{a b}",
+		},
+		{
+			"NestedGarbage",
+			`{"a": {"x": [1, : 2]}, "b": 3}`,
+			[]string{"unexpected ':' in state ArrayValueOrEnd"},
+			"{a b}",
+		},
+		{
+			"Unclosed",
+			`{"a": [1, 2`,
+			[]string{"unexpected end of input"},
+			"{a}",
+		},
+		{
+			"ExtraValue",
+			`{} {"a": 1} ]`,
+			[]string{"extra '{' after end of value"},
+			"{}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := NewParser(lexer.NewLexer(tt.input), Recover(0)).ParseValue()
+
+			var list ErrorList
+			if !errors.As(err, &list) {
+				t.Fatalf("Expected an ErrorList, got %v", err)
+			}
+			var messages []string
+			for _, e := range list {
+				messages = append(messages, e.Msg)
+			}
+			if !slices.Equal(messages, tt.messages) {
+				t.Errorf("Got errors %q, expected %q", messages, tt.messages)
+			}
+			if got := summarize(v); got != tt.tree {
+				t.Errorf("Got tree %s, expected %s", got, tt.tree)
+			}
+		})
+	}
+}
+
+// summarize lists the keys of an object or the numbers in an array.
+func summarize(v Value) string {
+	switch v := v.(type) {
+	case *Object:
+		s := "{"
+		for i, key := range v.Keys() {
+			if i > 0 {
+				s += " "
+			}
+			s += key
+		}
+		return s + "}"
+	case *Array:
+		s := "["
+		for i, e := range v.All() {
+			if i > 0 {
+				s += " "
+			}
+			if n, ok := e.(*Number); ok {
+				s += n.Literal
+			}
+		}
+		return s + "]"
+	}
+	return "?"
+}
+
+func TestRecover_Limit(t *testing.T) {
+	err := NewParser(lexer.NewLexer(`[x, x, x, x]`), Recover(2)).Parse()
+	var list ErrorList
+	if !errors.As(err, &list) || len(list) != 2 {
+		t.Fatalf("Expected 2 errors, got %v", err)
+	}
+	if msg := err.Error(); !strings.HasSuffix(msg, " (and 1 more error)") {
+		t.Errorf("Expected the message to count one more error, got %q", msg)
+	}
+
+	// errors.As also finds the first ParseError in the list.
+	var perr *ParseError
+	if !errors.As(err, &perr) || perr != list[0] {
+		t.Errorf("Expected errors.As to find the first error, got %v", perr)
+	}
+	expected := append(slices.Clone(valueTokens), lexer.TokenSquareClose)
+	if !slices.Equal(perr.Expected, expected) {
+		t.Errorf("Expected the first error to expect %v, got %v", expected, perr.Expected)
+	}
+}
+
+func TestRecover_Valid(t *testing.T) {
+	if err := NewParser(lexer.NewLexer(`{"a": [1, 2]}`), Recover(0)).Parse(); err != nil {
+		t.Errorf("Expected no error for valid input, got %v", err)
+	}
+}
+
+func TestRecover_TooDeep(t *testing.T) {
+	err := NewParser(lexer.NewLexer(`[[[[1]]], 2, [3 4]]`), MaxDepth(2), Recover(0)).Parse()
+	var list ErrorList
+	if !errors.As(err, &list) || len(list) != 2 {
+		t.Fatalf("Expected 2 errors, got %v", err)
+	}
+	if !errors.Is(list[0], ErrTooDeep) {
+		t.Errorf("Expected the first error to wrap ErrTooDeep, got %v", list[0])
+	}
+}
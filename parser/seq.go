@@ -0,0 +1,146 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"iter"
+
+	"github.com/HrithikSawant/go-json-parser/lexer"
+)
+
+// RecordSeparator is the ASCII RS character that starts every text of a
+// JSON text sequence.
+const RecordSeparator = 0x1E
+
+// ErrTruncated is wrapped by the ParseError a SeqReader reports for a
+// number, true, false or null that is not followed by whitespace, which
+// RFC 7464 requires parsers to treat as possibly truncated.
+var ErrTruncated = errors.New("possibly truncated JSON text")
+
+// SeqReader reads a JSON text sequence (RFC 7464, application/json-seq):
+// documents that each start with RecordSeparator and end with a line
+// feed. Every text is parsed on its own, so an invalid one does not affect
+// the texts after it. Empty texts, as between two separators, are skipped.
+// Positions in records refer to the whole stream.
+type SeqReader struct {
+	r       *bufio.Reader
+	lex     *lexer.Lexer
+	p       *Parser
+	pos     lexer.Position // start of the next text
+	started bool           // the first separator has been read
+	summary RecordSummary
+	err     error // read error
+}
+
+// NewSeqReader returns a reader of the texts of r. The lexer l, which must
+// have been created by lexer.NewBytesLexer, is reset for every text and
+// supplies the lexer options; opts configure the parser of each text.
+func NewSeqReader(r io.Reader, l *lexer.Lexer, opts ...Option) *SeqReader {
+	return &SeqReader{
+		r:   bufio.NewReader(r),
+		lex: l,
+		p:   NewParser(l, opts...),
+		pos: lexer.Position{Line: 1, Column: 1},
+	}
+}
+
+// Records returns an iterator over the records of the remaining texts. It
+// stops at the end of the input or at a read error, which Err returns.
+func (s *SeqReader) Records() iter.Seq[Record] {
+	return func(yield func(Record) bool) {
+		for {
+			rec, ok := s.next()
+			if !ok || !yield(rec) {
+				return
+			}
+		}
+	}
+}
+
+// next reads and parses the next text that is not empty. Anything before
+// the first separator is reported as an invalid text.
+func (s *SeqReader) next() (Record, bool) {
+	for s.err == nil {
+		text, err := s.r.ReadBytes(RecordSeparator)
+		if err != nil && !errors.Is(err, io.EOF) {
+			s.err = err
+			return Record{}, false
+		}
+		if err == nil {
+			text = text[:len(text)-1]
+		} else if len(text) == 0 {
+			return Record{}, false
+		}
+
+		pos := s.pos
+		s.pos = advance(pos, text)
+		if err == nil {
+			s.pos.Offset++
+			s.pos.Column++
+		}
+		first := !s.started
+		s.started = true
+
+		if len(bytes.TrimSpace(text)) == 0 {
+			if !first {
+				s.summary.Blank++
+			}
+			continue
+		}
+		if first {
+			s.summary.Invalid++
+			perr := &ParseError{Pos: pos, Msg: "JSON text sequence must start with a record separator (0x1E)"}
+			return Record{Line: pos.Line, Err: perr}, true
+		}
+
+		s.lex.ResetBytesAt(text, pos)
+		v, err := s.p.ParseValue()
+		if err == nil && !isSpace(text[len(text)-1]) {
+			switch v.(type) {
+			case *Number, *Bool, *Null:
+				err = &ParseError{
+					Pos: v.Pos(),
+					Msg: "possibly truncated JSON text: a top-level number, true, false or null must be followed by whitespace",
+					Err: ErrTruncated,
+				}
+				v = nil
+			}
+		}
+		if err != nil {
+			s.summary.Invalid++
+		} else {
+			s.summary.Valid++
+		}
+		return Record{Line: pos.Line, Value: v, Err: err, Diagnostics: s.p.Diagnostics()}, true
+	}
+	return Record{}, false
+}
+
+// Err returns the error that stopped reading the input, if any.
+func (s *SeqReader) Err() error {
+	return s.err
+}
+
+// Summary returns the counts of the records read so far.
+func (s *SeqReader) Summary() RecordSummary {
+	return s.summary
+}
+
+// advance returns the position just past text, which starts at pos.
+func advance(pos lexer.Position, text []byte) lexer.Position {
+	pos.Offset += len(text)
+	if n := bytes.Count(text, []byte("\n")); n > 0 {
+		pos.Line += n
+		pos.Column = len(text) - bytes.LastIndexByte(text, '\n')
+	} else {
+		pos.Column += len(text)
+	}
+	return pos
+}
+
+// isSpace reports whether ch is JSON whitespace.
+func isSpace(ch byte) bool {
+	return ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r'
+}
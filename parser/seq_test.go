@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/HrithikSawant/go-json-parser/lexer"
+)
+
+func TestSeqReader(t *testing.T) {
+	input := "\x1e{\"a\": 1}\n\x1e\x1e[1,\n2]\n\x1e42\x1e\"x\"\n\x1etrue\n\x1e{\"b\": \n\x1e123"
+	s := NewSeqReader(strings.NewReader(input), lexer.NewBytesLexer(nil))
+
+	type result struct {
+		line int
+		err  string
+	}
+	var got []result
+	for rec := range s.Records() {
+		r := result{line: rec.Line}
+		if rec.Err != nil {
+			r.err = rec.Err.Error()
+		} else if rec.Value == nil {
+			t.Errorf("Line %d: expected a value", rec.Line)
+		}
+		got = append(got, r)
+	}
+	if s.Err() != nil {
+		t.Fatalf("Unexpected read error: %v", s.Err())
+	}
+
+	truncated := ": possibly truncated JSON text: a top-level number, true, false or null must be followed by whitespace"
+	expected := []result{
+		{1, ""},
+		{2, ""},
+		{4, "line 4, column 2" + truncated},
+		{4, ""},
+		{5, ""},
+		{6, "line 7, column 1: unexpected end of input"},
+		{7, "line 7, column 2" + truncated},
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("Got records %v, expected %v", got, expected)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("Record %d - got %+v, expected %+v", i, got[i], expected[i])
+		}
+	}
+	if sum := s.Summary(); sum != (RecordSummary{Valid: 4, Invalid: 3, Blank: 1}) {
+		t.Errorf("Got summary %+v", sum)
+	}
+}
+
+func TestSeqReader_Truncated(t *testing.T) {
+	s := NewSeqReader(strings.NewReader("\x1e  12"), lexer.NewBytesLexer(nil))
+	for rec := range s.Records() {
+		if !errors.Is(rec.Err, ErrTruncated) || rec.Value != nil {
+			t.Errorf("Expected ErrTruncated, got (%v, %v)", rec.Value, rec.Err)
+		}
+		// The error points at the value, not past it.
+		var perr *ParseError
+		if errors.As(rec.Err, &perr) && (perr.Pos.Column != 4 || perr.Found.Type != lexer.TokenInvalid) {
+			t.Errorf("Expected the error at column 4 with no token, got %s and %q", perr.Pos, perr.Found.Type)
+		}
+	}
+}
+
+// Text before the first separator is not part of any record.
+func TestSeqReader_MissingSeparator(t *testing.T) {
+	s := NewSeqReader(strings.NewReader("{}\n\x1e[]\n"), lexer.NewBytesLexer(nil))
+
+	var errs []error
+	for rec := range s.Records() {
+		errs = append(errs, rec.Err)
+	}
+	if len(errs) != 2 || errs[0] == nil || errs[1] != nil {
+		t.Errorf("Expected an error for the first text only, got %v", errs)
+	}
+}
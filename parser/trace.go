@@ -0,0 +1,42 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/HrithikSawant/go-json-parser/lexer"
+)
+
+// Tracer follows the parser through its state machine. Trace is called once
+// for every token the parser reads.
+type Tracer interface {
+	Trace(ev TraceEvent)
+}
+
+// TraceEvent is a token read by the parser and the state it was read in.
+type TraceEvent struct {
+	State string      // parser state, e.g. "ExpectColon"
+	Token lexer.Token // the token read
+	Depth int         // number of enclosing objects and arrays
+}
+
+// TableTracer writes every event as a row of a table.
+type TableTracer struct {
+	W io.Writer
+}
+
+// NewTableTracer returns a Tracer that writes to w.
+func NewTableTracer(w io.Writer) *TableTracer {
+	return &TableTracer{W: w}
+}
+
+func (t *TableTracer) Trace(ev TraceEvent) {
+	fmt.Fprintf(t.W, "DEBUG: State = %-20s | Token = %-10s | Literal = %s\n", ev.State, ev.Token.Type, ev.Token.Literal)
+}
+
+// trace reports tok, read in state s, to the tracer if there is one.
+func (p *Parser) trace(s parserState, tok lexer.Token) {
+	if p.tracer != nil {
+		p.tracer.Trace(TraceEvent{State: s.String(), Token: tok, Depth: len(p.stack) - 1})
+	}
+}
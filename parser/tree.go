@@ -0,0 +1,101 @@
+package parser
+
+import "github.com/HrithikSawant/go-json-parser/lexer"
+
+// treeBuilder is the Handler that assembles the document tree for
+// ParseValue.
+type treeBuilder struct {
+	p          *Parser // for the span of the current token
+	root       Value
+	open       []Value // the *Object and *Array values not yet closed
+	key        string  // key of the next member of the innermost object
+	keyPos     lexer.Position
+	dup        bool // key is already in the innermost object
+	duplicates DuplicateKeyPolicy
+}
+
+// add attaches v to the innermost open container, or makes it the root.
+func (b *treeBuilder) add(v Value) {
+	if len(b.open) == 0 {
+		b.root = v
+		return
+	}
+	switch parent := b.open[len(b.open)-1].(type) {
+	case *Object:
+		if b.dup {
+			switch b.duplicates {
+			case DuplicateFirstWins:
+				return
+			case DuplicateLastWins:
+				for i := range parent.Members {
+					if parent.Members[i].Key == b.key {
+						parent.Members[i].KeyPos, parent.Members[i].Value = b.keyPos, v
+						return
+					}
+				}
+			}
+		}
+		parent.Members = append(parent.Members, Member{Key: b.key, KeyPos: b.keyPos, Value: v})
+	case *Array:
+		parent.Elements = append(parent.Elements, v)
+	}
+}
+
+// span returns the span of the token the parser is reporting, which starts
+// at pos.
+func (b *treeBuilder) span(pos lexer.Position) span {
+	return span{pos: pos, end: b.p.tok.End}
+}
+
+func (b *treeBuilder) StartObject(pos lexer.Position) error {
+	o := &Object{span: span{pos: pos}}
+	b.add(o)
+	b.open = append(b.open, o)
+	return nil
+}
+
+func (b *treeBuilder) StartArray(pos lexer.Position) error {
+	a := &Array{span: span{pos: pos}}
+	b.add(a)
+	b.open = append(b.open, a)
+	return nil
+}
+
+func (b *treeBuilder) EndObject(pos lexer.Position) error {
+	b.open[len(b.open)-1].(*Object).end = b.p.tok.End
+	b.open = b.open[:len(b.open)-1]
+	return nil
+}
+
+func (b *treeBuilder) EndArray(pos lexer.Position) error {
+	b.open[len(b.open)-1].(*Array).end = b.p.tok.End
+	b.open = b.open[:len(b.open)-1]
+	return nil
+}
+
+func (b *treeBuilder) Key(key string, pos lexer.Position) error {
+	b.key, b.keyPos, b.dup = key, pos, b.p.dupKey
+	return nil
+}
+
+func (b *treeBuilder) String(value string, pos lexer.Position) error {
+	b.add(&String{span: b.span(pos), Value: value})
+	return nil
+}
+
+func (b *treeBuilder) Number(literal string, kind lexer.NumberKind, pos lexer.Position) error {
+	b.add(&Number{span: b.span(pos), Literal: literal, NumberKind: kind})
+	return nil
+}
+
+func (b *treeBuilder) Bool(value bool, pos lexer.Position) error {
+	b.add(&Bool{span: b.span(pos), Value: value})
+	return nil
+}
+
+func (b *treeBuilder) Null(pos lexer.Position) error {
+	b.add(&Null{span: b.span(pos)})
+	return nil
+}
+
+func (b *treeBuilder) Error(err *ParseError) {}
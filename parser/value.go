@@ -0,0 +1,199 @@
+package parser
+
+import (
+	"iter"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/HrithikSawant/go-json-parser/lexer"
+)
+
+// Kind is the type of a JSON value.
+type Kind int
+
+const (
+	KindNull Kind = iota
+	KindBool
+	KindNumber
+	KindString
+	KindArray
+	KindObject
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindNull:
+		return "null"
+	case KindBool:
+		return "bool"
+	case KindNumber:
+		return "number"
+	case KindString:
+		return "string"
+	case KindArray:
+		return "array"
+	case KindObject:
+		return "object"
+	default:
+		return "unknown"
+	}
+}
+
+// Value is a node of the document tree built by ParseValue: one of *Object,
+// *Array, *String, *Number, *Bool or *Null.
+type Value interface {
+	Kind() Kind
+	Pos() lexer.Position // where the value starts
+	End() int            // byte offset just past the value
+}
+
+// span records where a value is in the input.
+type span struct {
+	pos lexer.Position
+	end int
+}
+
+func (s span) Pos() lexer.Position { return s.pos }
+func (s span) End() int            { return s.end }
+
+// Object is a JSON object. Members are kept in input order, including
+// members with the same key.
+type Object struct {
+	span
+	Members []Member
+}
+
+// Member is a key/value pair of an Object.
+type Member struct {
+	Key    string         // the decoded key
+	KeyPos lexer.Position // where the key starts
+	Value  Value
+}
+
+func (o *Object) Kind() Kind { return KindObject }
+
+// Len returns the number of members.
+func (o *Object) Len() int {
+	return len(o.Members)
+}
+
+// Get returns the value of the last member named key.
+func (o *Object) Get(key string) (Value, bool) {
+	for i := len(o.Members) - 1; i >= 0; i-- {
+		if o.Members[i].Key == key {
+			return o.Members[i].Value, true
+		}
+	}
+	return nil, false
+}
+
+// Keys returns the member keys in input order.
+func (o *Object) Keys() []string {
+	keys := make([]string, len(o.Members))
+	for i, m := range o.Members {
+		keys[i] = m.Key
+	}
+	return keys
+}
+
+// All iterates over the members in input order.
+func (o *Object) All() iter.Seq2[string, Value] {
+	return func(yield func(string, Value) bool) {
+		for _, m := range o.Members {
+			if !yield(m.Key, m.Value) {
+				return
+			}
+		}
+	}
+}
+
+// Array is a JSON array.
+type Array struct {
+	span
+	Elements []Value
+}
+
+func (a *Array) Kind() Kind { return KindArray }
+
+// Len returns the number of elements.
+func (a *Array) Len() int {
+	return len(a.Elements)
+}
+
+// At returns the element at index i.
+func (a *Array) At(i int) Value {
+	return a.Elements[i]
+}
+
+// All iterates over the elements and their indexes.
+func (a *Array) All() iter.Seq2[int, Value] {
+	return func(yield func(int, Value) bool) {
+		for i, v := range a.Elements {
+			if !yield(i, v) {
+				return
+			}
+		}
+	}
+}
+
+// String is a JSON string.
+type String struct {
+	span
+	Value string // the decoded string
+}
+
+func (s *String) Kind() Kind { return KindString }
+
+// Number is a JSON number, kept as written so that no precision is lost
+// until it is converted.
+type Number struct {
+	span
+	Literal    string
+	NumberKind lexer.NumberKind // integer or float
+}
+
+func (n *Number) Kind() Kind { return KindNumber }
+
+// Int64 returns the number as an int64. It fails for numbers that have a
+// fraction or exponent or do not fit.
+func (n *Number) Int64() (int64, error) {
+	if isHex(n.Literal) {
+		return strconv.ParseInt(n.Literal, 0, 64)
+	}
+	return strconv.ParseInt(n.Literal, 10, 64)
+}
+
+// Float64 returns the number as the nearest float64.
+func (n *Number) Float64() (float64, error) {
+	switch {
+	case isHex(n.Literal):
+		i, err := strconv.ParseInt(n.Literal, 0, 64)
+		return float64(i), err
+	case strings.HasSuffix(n.Literal, "NaN"):
+		// JSON5 allows a sign, which ParseFloat does not.
+		return math.NaN(), nil
+	}
+	return strconv.ParseFloat(n.Literal, 64)
+}
+
+// isHex reports whether literal is a JSON5 hexadecimal number.
+func isHex(literal string) bool {
+	literal = strings.TrimLeft(literal, "+-")
+	return strings.HasPrefix(literal, "0x") || strings.HasPrefix(literal, "0X")
+}
+
+// Bool is true or false.
+type Bool struct {
+	span
+	Value bool
+}
+
+func (b *Bool) Kind() Kind { return KindBool }
+
+// Null is null.
+type Null struct {
+	span
+}
+
+func (n *Null) Kind() Kind { return KindNull }
@@ -0,0 +1,217 @@
+package parser
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/HrithikSawant/go-json-parser/lexer"
+)
+
+func parseValue(t *testing.T, input string, opts ...Option) Value {
+	t.Helper()
+	v, err := NewParser(lexer.NewLexer(input), opts...).ParseValue()
+	if err != nil {
+		t.Fatalf("ParseValue(%q) failed: %v", input, err)
+	}
+	return v
+}
+
+func TestParseValue_Object(t *testing.T) {
+	input := `{
+		"name": "Alice",
+		"age": 30,
+		"height": 1.68,
+		"student": false,
+		"spouse": null,
+		"scores": [98, 87],
+		"profile": {"email": "alice@example.com"}
+	}`
+	v := parseValue(t, input)
+
+	obj, ok := v.(*Object)
+	if !ok {
+		t.Fatalf("Expected *Object, got %T", v)
+	}
+	if keys := obj.Keys(); !slices.Equal(keys, []string{"name", "age", "height", "student", "spouse", "scores", "profile"}) {
+		t.Errorf("Unexpected keys %v", keys)
+	}
+	if obj.Pos().Offset != 0 || obj.End() != len(input) {
+		t.Errorf("Expected object to span the input, got %d-%d", obj.Pos().Offset, obj.End())
+	}
+
+	name, _ := obj.Get("name")
+	if s, ok := name.(*String); !ok || s.Value != "Alice" {
+		t.Errorf("name - got %#v", name)
+	}
+
+	age, _ := obj.Get("age")
+	if n, ok := age.(*Number); !ok || n.NumberKind != lexer.NumberInteger {
+		t.Errorf("age - got %#v", age)
+	} else if i, err := n.Int64(); err != nil || i != 30 {
+		t.Errorf("age - Int64() = (%d, %v)", i, err)
+	}
+
+	height, _ := obj.Get("height")
+	if f, err := height.(*Number).Float64(); err != nil || f != 1.68 {
+		t.Errorf("height - Float64() = (%v, %v)", f, err)
+	}
+
+	student, _ := obj.Get("student")
+	if b, ok := student.(*Bool); !ok || b.Value {
+		t.Errorf("student - got %#v", student)
+	}
+
+	spouse, _ := obj.Get("spouse")
+	if spouse.Kind() != KindNull {
+		t.Errorf("spouse - got kind %s", spouse.Kind())
+	}
+
+	scores, _ := obj.Get("scores")
+	arr := scores.(*Array)
+	var got []string
+	for i, elem := range arr.All() {
+		if i != len(got) {
+			t.Errorf("Unexpected index %d", i)
+		}
+		got = append(got, elem.(*Number).Literal)
+	}
+	if !slices.Equal(got, []string{"98", "87"}) {
+		t.Errorf("scores - got %v", got)
+	}
+
+	profile, _ := obj.Get("profile")
+	email, ok := profile.(*Object).Get("email")
+	if !ok || email.(*String).Value != "alice@example.com" {
+		t.Errorf("profile.email - got %#v", email)
+	}
+
+	if _, ok := obj.Get("missing"); ok {
+		t.Errorf("Expected Get of a missing key to fail")
+	}
+}
+
+func TestParseValue_DecodesStrings(t *testing.T) {
+	v := parseValue(t, `{"caf\u00e9": "tab\tquote\" \ud800"}`)
+
+	for key, value := range v.(*Object).All() {
+		if key != "café" {
+			t.Errorf("Expected decoded key, got %q", key)
+		}
+		if s := value.(*String).Value; s != "tab\tquote\" �" {
+			t.Errorf("Expected decoded value, got %q", s)
+		}
+	}
+
+	_, err := NewParser(lexer.NewLexer(`["\ud800"]`), WithSurrogatePolicy(lexer.SurrogateError)).ParseValue()
+	if err == nil {
+		t.Errorf("Expected a lone surrogate to fail with SurrogateError")
+	}
+}
+
+// Parse, which decodes no strings for a tree, rejects lone surrogates
+// under SurrogateError just as ParseValue does.
+func TestParse_SurrogateError(t *testing.T) {
+	for _, input := range []string{`"\ud800"`, `{"\ud800": 1}`, `[1, "a\udc00"]`} {
+		parseErr := NewParser(lexer.NewLexer(input), WithSurrogatePolicy(lexer.SurrogateError)).Parse()
+		_, valueErr := NewParser(lexer.NewLexer(input), WithSurrogatePolicy(lexer.SurrogateError)).ParseValue()
+		if parseErr == nil || valueErr == nil {
+			t.Errorf("%s: expected both to fail, got Parse %v and ParseValue %v", input, parseErr, valueErr)
+		}
+		if err := NewParser(lexer.NewLexer(input)).Parse(); err != nil {
+			t.Errorf("%s: unexpected error with the default policy: %v", input, err)
+		}
+	}
+}
+
+func TestParseValue_KeepsDuplicateMembers(t *testing.T) {
+	obj := parseValue(t, `{"a": 1, "b": 2, "a": 3}`).(*Object)
+
+	if obj.Len() != 3 {
+		t.Fatalf("Expected 3 members, got %d", obj.Len())
+	}
+	if v, _ := obj.Get("a"); v.(*Number).Literal != "3" {
+		t.Errorf("Expected Get to return the last member, got %s", v.(*Number).Literal)
+	}
+}
+
+func TestParseValue_Invalid(t *testing.T) {
+	v, err := NewParser(lexer.NewLexer(`{"a": [1, 2}`)).ParseValue()
+	if err == nil || v != nil {
+		t.Errorf("Expected (nil, error), got (%v, %v)", v, err)
+	}
+}
+
+func TestParseValue_TopLevelScalar(t *testing.T) {
+	v := parseValue(t, ` "hello" `)
+	if s, ok := v.(*String); !ok || s.Value != "hello" || s.Pos().Offset != 1 || s.End() != 8 {
+		t.Errorf("Expected the string \"hello\" at 1-8, got %#v", v)
+	}
+}
+
+func TestParseValue_DuplicateKeyPolicies(t *testing.T) {
+	input := `{"a": 1, "b": 2, "a": {"x": 3}, "a": 4}`
+	tests := []struct {
+		policy DuplicateKeyPolicy
+		keys   []string
+		values []string
+	}{
+		{DuplicateKeepAll, []string{"a", "b", "a", "a"}, []string{"1", "2", "object", "4"}},
+		{DuplicateWarn, []string{"a", "b", "a", "a"}, []string{"1", "2", "object", "4"}},
+		{DuplicateFirstWins, []string{"a", "b"}, []string{"1", "2"}},
+		{DuplicateLastWins, []string{"a", "b"}, []string{"4", "2"}},
+	}
+
+	for _, tt := range tests {
+		obj := parseValue(t, input, WithDuplicateKeys(tt.policy)).(*Object)
+
+		var values []string
+		for _, v := range obj.All() {
+			if n, ok := v.(*Number); ok {
+				values = append(values, n.Literal)
+			} else {
+				values = append(values, v.Kind().String())
+			}
+		}
+		if !slices.Equal(obj.Keys(), tt.keys) || !slices.Equal(values, tt.values) {
+			t.Errorf("Policy %d - got %v %v, expected %v %v", tt.policy, obj.Keys(), values, tt.keys, tt.values)
+		}
+	}
+}
+
+func TestParseValue_JSON5(t *testing.T) {
+	input := `{key: 'it\'s', 'quoted': "multi\
+line", hex: -0x1F, inf: +Infinity, nan: -NaN,}`
+	lex := lexer.NewLexer(input, lexer.WithDialect(lexer.DialectJSON5))
+	v, err := NewParser(lex).ParseValue()
+	if err != nil {
+		t.Fatalf("ParseValue failed: %v", err)
+	}
+
+	obj := v.(*Object)
+	if keys := obj.Keys(); !slices.Equal(keys, []string{"key", "quoted", "hex", "inf", "nan"}) {
+		t.Errorf("Unexpected keys %v", keys)
+	}
+	if s, _ := obj.Get("key"); s.(*String).Value != "it's" {
+		t.Errorf("key - got %q", s.(*String).Value)
+	}
+	if s, _ := obj.Get("quoted"); s.(*String).Value != "multiline" {
+		t.Errorf("quoted - got %q", s.(*String).Value)
+	}
+
+	hex, _ := obj.Get("hex")
+	if i, err := hex.(*Number).Int64(); err != nil || i != -31 {
+		t.Errorf("hex - Int64() = (%d, %v)", i, err)
+	}
+	if f, err := hex.(*Number).Float64(); err != nil || f != -31 {
+		t.Errorf("hex - Float64() = (%v, %v)", f, err)
+	}
+	inf, _ := obj.Get("inf")
+	if f, err := inf.(*Number).Float64(); err != nil || !math.IsInf(f, 1) {
+		t.Errorf("inf - Float64() = (%v, %v)", f, err)
+	}
+	nan, _ := obj.Get("nan")
+	if f, err := nan.(*Number).Float64(); err != nil || !math.IsNaN(f) {
+		t.Errorf("nan - Float64() = (%v, %v)", f, err)
+	}
+}